@@ -2,10 +2,16 @@ package main
 
 import (
 	"authentication-service/internal/config"
+	"authentication-service/internal/domain"
+	"authentication-service/internal/fixtures"
+	"authentication-service/internal/geo"
 	handler "authentication-service/internal/handler/http"
 	customMiddleware "authentication-service/internal/handler/middleware"
+	"authentication-service/internal/mail"
+	"authentication-service/internal/migrations"
 	"authentication-service/internal/repository"
 	"authentication-service/internal/service"
+	"authentication-service/internal/wasteclient"
 	"context"
 	"log"
 	"net/http" // HTTP metodları için (http.MethodGet vs.) bunu eklemeyi unutma
@@ -41,28 +47,180 @@ func main() {
 		log.Fatalf("MongoDB not reachable: %v", err)
 	}
 	db := client.Database(cfg.DbName)
+	auditDb := client.Database(cfg.AuditDbName)
+
+	// Index'ler ve şema değişiklikleri; repository'ler bunlara güvendiği için uygulama açılmadan önce çalışır
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	if err := migrations.Run(migrateCtx, db, migrations.Auth); err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+	if err := migrations.Run(migrateCtx, auditDb, migrations.Audit); err != nil {
+		log.Fatalf("Audit migration failed: %v", err)
+	}
+	migrateCancel()
+
+	// Yönetim ve güvenlik olayları; waste servisi de aynı koleksiyona yazar
+	auditService := service.NewAuditService(repository.NewMongoAuditRepository(auditDb))
 
 	userRepo := repository.NewMongoRepository(db)
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret)
-	userService := service.NewUserService(userRepo)
-
-	// Seed demo users
-	if err := userRepo.SeedUsers(context.Background()); err != nil {
-		log.Printf("Seed users error: %v", err)
-	} else {
-		log.Println("Demo users seeded successfully")
+	sessionRepo := repository.NewMongoSessionRepository(db)
+	keyRepo := repository.NewMongoKeyRepository(db)
+
+	// Eski anahtar, onunla imzalanmış en uzun ömürlü token'ın (access, doğrulama linki) süresi dolana kadar yayında kalmalı
+	keyRetention := max(cfg.KeyRetention, cfg.AccessTokenTTL, cfg.VerificationTTL)
+	keyService, err := service.NewKeyService(keyRepo, cfg.JWTSigningAlg, cfg.KeyRotationInterval, keyRetention)
+	if err != nil {
+		log.Fatalf("Invalid signing configuration: %v", err)
+	}
+	if err := keyService.Refresh(ctx); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	keyService.Start(context.Background())
+
+	// Mail gönderimi: prod'da SMTP, geliştirmede outbox dizinine .eml dosyaları
+	var mailer domain.Mailer
+	switch cfg.MailDriver {
+	case "smtp":
+		mailer = mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	case "outbox":
+		mailer = mail.NewOutboxMailer(cfg.MailOutboxDir, cfg.MailFrom)
+	default:
+		log.Fatalf("Unknown MAIL_DRIVER: %s", cfg.MailDriver)
+	}
+
+	tokenRepo := repository.NewMongoOneTimeTokenRepository(db)
+	passwordService := service.NewPasswordService(userRepo, tokenRepo, sessionRepo, mailer, cfg.AppBaseURL, cfg.PasswordResetTTL, auditService)
+	verificationService := service.NewVerificationService(userRepo, keyService, mailer, cfg.AppBaseURL, cfg.VerificationTTL)
+
+	loginThrottle := service.NewLoginThrottle(repository.NewMongoThrottleRepository(db), service.ThrottleConfig{
+		FreeAttempts:    cfg.LoginFreeAttempts,
+		MaxAttempts:     cfg.LoginMaxAttempts,
+		IPFreeAttempts:  cfg.LoginIPFreeAttempts,
+		IPMaxAttempts:   cfg.LoginIPMaxAttempts,
+		BackoffBase:     cfg.LoginBackoffBase,
+		BackoffMax:      cfg.LoginBackoffMax,
+		LockoutDuration: cfg.LoginLockoutDuration,
+		FailureWindow:   cfg.LoginFailureWindow,
+	})
+
+	roleRepo := repository.NewMongoRoleRepository(db)
+	if err := roleRepo.EnsureDefaults(context.Background(), domain.DefaultRoles); err != nil {
+		log.Fatalf("Default roles error: %v", err)
+	}
+	roleService := service.NewRoleService(roleRepo, userRepo, auditService)
+
+	// Adresler gömülü il/ilçe merkez verisiyle koordinata çevrilir
+	geocoder, err := geo.NewOfflineGeocoder()
+	if err != nil {
+		log.Fatalf("Geocoder error: %v", err)
+	}
+
+	// Belediyeler (tenant'lar); kullanıcıların token'larına "org" claim'i olarak yazılır
+	orgService := service.NewOrganizationService(repository.NewMongoOrganizationRepository(db), auditService)
+
+	mfaService := service.NewMFAService(userRepo, cfg.MFAIssuer, cfg.MFARequiredRoles, auditService)
+
+	authService := service.NewAuthService(userRepo, sessionRepo, keyService, verificationService, loginThrottle, mfaService, roleService, geocoder, orgService, auditService, service.AuthConfig{
+		AccessTokenTTL:       cfg.AccessTokenTTL,
+		RefreshTokenTTL:      cfg.RefreshTokenTTL,
+		AllowUnverifiedLogin: cfg.UnverifiedLoginPolicy != "deny",
+		MFAChallengeTTL:      cfg.MFAChallengeTTL,
+	})
+	userService := service.NewUserService(userRepo, roleRepo, loginThrottle, geocoder, verificationService, auditService)
+	addressService := service.NewAddressService(userRepo, geocoder)
+
+	// Saklama süresini aşan silinmiş kullanıcılar periyodik olarak kalıcı silinir
+	invitationRepo := repository.NewMongoInvitationRepository(db)
+	invitationService := service.NewInvitationService(invitationRepo, userRepo, userService, roleRepo, orgService, mailer, cfg.AppBaseURL, cfg.InvitationTTL, auditService)
+	sessionService := service.NewSessionService(sessionRepo, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, auditService)
+	apiKeyRepo := repository.NewMongoAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, roleService, orgService, auditService, service.APIKeyConfig{
+		DefaultTTL: cfg.APIKeyDefaultTTL,
+		MaxTTL:     cfg.APIKeyMaxTTL,
+		MaxPerUser: cfg.APIKeyMaxPerUser,
+	})
+	oauthClientRepo := repository.NewMongoOAuthClientRepository(db)
+	oauthClientService := service.NewOAuthClientService(oauthClientRepo, sessionRepo, auditService)
+	authCodeRepo := repository.NewMongoAuthorizationCodeRepository(db)
+	oidcService := service.NewOIDCService(oauthClientRepo, authCodeRepo, userRepo, sessionRepo, authService, keyService, apiKeyService, service.OIDCConfig{
+		Issuer:         cfg.OIDCIssuer,
+		CodeTTL:        cfg.OIDCCodeTTL,
+		AccessTokenTTL: cfg.AccessTokenTTL,
+	})
+	// Waste servisi token introspection ve iptal listesi için kendi istemci kimliğiyle gelir
+	if cfg.ServiceClientSecret != "" {
+		if err := oauthClientService.EnsureServiceClient(context.Background(), cfg.ServiceClientID, cfg.ServiceClientSecret, domain.WasteServiceScopes); err != nil {
+			log.Fatalf("Service client error: %v", err)
+		}
+	}
+	// KVKK/GDPR isteklerinde waste servisine giden token ayrı bir kimlikle alınır; waste servisi
+	// kendi secret'ıyla kullanıcı verisini dışa aktaran veya silen token alamaz
+	if cfg.PrivacyClientSecret != "" {
+		if cfg.PrivacyClientID == cfg.ServiceClientID {
+			log.Fatalf("PRIVACY_CLIENT_ID must differ from SERVICE_CLIENT_ID")
+		}
+		if err := oauthClientService.EnsureServiceClient(context.Background(), cfg.PrivacyClientID, cfg.PrivacyClientSecret, domain.PrivacyScopes); err != nil {
+			log.Fatalf("Privacy client error: %v", err)
+		}
 	}
+	// Destek personeli kullanıcının gözünden bakmak için kısa süreli oturum açabilir
+	impersonationService := service.NewImpersonationService(userRepo, sessionRepo, authService, roleService, auditService, cfg.ImpersonationTTL)
+	retentionService := service.NewRetentionService(userRepo, sessionRepo, tokenRepo, apiKeyRepo, invitationRepo, authCodeRepo, loginThrottle, repository.NewMongoRetentionReportRepository(db), cfg.UserRetentionPeriod, cfg.UserRetentionInterval, auditService)
+	retentionService.Start(context.Background())
 
-	// AuthHandler düzeltmesi: Önceki hatanı hatırlayarak, buraya config'i eklemen gerekebilir.
-	// Eğer NewAuthHandler fonksiyonunu düzelttiysen şöyle olmalı:
-	// authHandler := handler.NewAuthHandler(authService, cfg)
-	// Ama şimdilik senin attığın koda sadık kalıyorum:
-	authHandler := handler.NewAuthHandler(authService)
+	// KVKK/GDPR: dışa aktarma ve silme iki servisi birlikte kapsar
+	// Waste servisine kullanıcının token'ı iletilmez; privacy istemcisinin kimliğiyle gidilir
+	wasteClient := wasteclient.NewClient(cfg.WasteServiceURL, cfg.WasteServiceTimeout, oidcService, cfg.PrivacyClientID, cfg.PrivacyClientSecret)
+	privacyService := service.NewPrivacyService(userRepo, retentionService, repository.NewMongoErasureRepository(db), wasteClient, auditService)
+
+	// Demo kullanıcılar sadece açıkça istenirse ve production dışında yüklenir
+	if cfg.FixturesEnabled {
+		file, err := fixtures.Load(cfg.FixturesFile)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		if err := fixtures.Apply(context.Background(), cfg.AppEnv, userRepo, file); err != nil {
+			log.Fatalf("Failed to apply fixtures: %v", err)
+		}
+	}
+
+	// Tüm korumalı rotalar aynı JWT middleware'ini kullanır (imza + oturum iptali kontrolü, X-API-Key)
+	jwtAuth := customMiddleware.JWTMiddleware(keyService, authService, apiKeyService)
+	// Hesabın kimlik bilgilerini değiştiren uçlar API anahtarıyla çağrılamaz
+	denyAPIKey := customMiddleware.DenyAPIKey()
+	// Impersonation token'ları yönetim uçlarına ve hesabın kimlik bilgilerine erişemez
+	denyImpersonation := customMiddleware.DenyImpersonation()
+	credentialRoutes := []echo.MiddlewareFunc{denyAPIKey, denyImpersonation}
+	interactiveAuth := func(next echo.HandlerFunc) echo.HandlerFunc { return jwtAuth(denyAPIKey(denyImpersonation(next))) }
+
+	authHandler := handler.NewAuthHandler(authService, keyService, jwtAuth)
 
 	userHandler := handler.NewUserHandler(userService)
+	passwordHandler := handler.NewPasswordHandler(passwordService)
+	verificationHandler := handler.NewVerificationHandler(verificationService)
+	mfaHandler := handler.NewMFAHandler(mfaService, interactiveAuth)
+	roleHandler := handler.NewRoleHandler(roleService)
+	addressHandler := handler.NewAddressHandler(addressService)
+	retentionHandler := handler.NewRetentionHandler(retentionService, userService)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	organizationHandler := handler.NewOrganizationHandler(orgService)
+	// Üçüncü taraf istemcilerin token'ları sadece userinfo'da geçerlidir
+	oidcHandler := handler.NewOIDCHandler(oidcService, authService, customMiddleware.DelegatedJWTMiddleware(keyService, authService))
+	oauthClientHandler := handler.NewOAuthClientHandler(oauthClientService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
 
 	// 5. Echo Server Kurulumu
 	e := echo.New()
+	// Login kilidi ve audit kayıtları istemci IP'sine dayanır; başlıklara sadece güvenilen proxy'lerden inanılır
+	ipExtractor, err := customMiddleware.IPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	// ==========================================
 	// 2. CORS AYARLARI BURAYA (En üste ekle)
@@ -70,35 +228,137 @@ func main() {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"}, // Geliştirme aşamasında "*" (herkes) iyidir. Prod'da "https://site.com" yaparsın.
 		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, customMiddleware.HeaderAPIKey},
 	}))
 
 	// Logger ve Recover middleware'lerini de eklemek iyi pratiktir:
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	e.Use(customMiddleware.AuditContext()) // Denetim kayıtları için IP ve user agent
 
 	// SWAGGER ROUTE
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	// ... (Rota tanımları aynı) ...
 	authHandler.RegisterRoutes(e)
+	passwordHandler.RegisterRoutes(e)
+	verificationHandler.RegisterRoutes(e)
+	mfaHandler.RegisterRoutes(e)
+	invitationHandler.RegisterRoutes(e)
+	oidcHandler.RegisterRoutes(e) // OpenID Connect sağlayıcı: discovery, authorize, token, introspect, userinfo
+
+	// Token'ları lokal doğrulayan servisler iptal edilen oturumları buradan takip eder (sadece servis token'ı ile)
+	e.GET("/auth/sessions/revoked", sessionHandler.Revoked, customMiddleware.RequireClientScope(oidcService, domain.ScopeSessionsRevoked))
+	// Destek personeli impersonation token'ı ile oturumu süresi dolmadan kapatır
+	e.POST("/auth/impersonation/end", impersonationHandler.EndCurrent, jwtAuth)
 
 	userGroup := e.Group("/users")
-	userGroup.Use(customMiddleware.JWTMiddleware(cfg.JWTSecret))
+	userGroup.Use(jwtAuth)
+
+	// Kendi hesabı: yetki alanları (rol, aktiflik, e-posta) bu uçlardan değişmez
+	userGroup.GET("/me", userHandler.Me)
+	userGroup.PUT("/me", userHandler.UpdateMe)
+	userGroup.PUT("/me/password", passwordHandler.ChangePassword, credentialRoutes...)
+	userGroup.GET("/me/export", privacyHandler.Export, credentialRoutes...)
+	userGroup.POST("/me/erase", privacyHandler.Erase, credentialRoutes...)
+	userGroup.GET("/me/sessions", sessionHandler.ListMine, credentialRoutes...)
+	userGroup.DELETE("/me/sessions/:id", sessionHandler.RevokeMine, credentialRoutes...)
+	userGroup.GET("/me/api-keys", apiKeyHandler.ListMine, credentialRoutes...)
+	userGroup.POST("/me/api-keys", apiKeyHandler.CreateMine, credentialRoutes...)
+	userGroup.DELETE("/me/api-keys/:id", apiKeyHandler.RevokeMine, credentialRoutes...)
+
+	// Başka organizasyonun kullanıcıları yokmuş gibi 404 döner; süper admin'ler sınırsızdır
+	sameOrg := customMiddleware.SameOrganization("id", userService)
+	superAdmin := customMiddleware.RequireSuperAdmin()
 
-	// Herkese açık user endpoints
-	userGroup.GET("", userHandler.GetAll)
-	userGroup.GET("/:id", userHandler.GetByID)
-	userGroup.PUT("/:id", userHandler.Update)
+	// /users/:id rotaları: kullanıcı kendi kaydına, yetkisi olan herkesinkine erişir.
+	// Liste e-posta ve adres içerdiği için sadece yetkisi olanlara açıktır.
+	userGroup.GET("", userHandler.List, customMiddleware.RequirePermission(domain.PermUsersRead))
+	userGroup.GET("/:id", userHandler.GetByID, customMiddleware.SelfOrPermission("id", domain.PermUsersRead), sameOrg)
+	// Güncelleme e-posta, rol ve aktiflik durumunu değiştirebildiği için API anahtarıyla
+	// ve kullanıcı yerine geçilmişken yapılamaz
+	userGroup.PUT("/:id", userHandler.Update, customMiddleware.SelfOrPermission("id", domain.PermUsersUpdate), sameOrg, denyAPIKey, denyImpersonation)
+
+	// Yönetim endpoint'leri: her rota kendi yetkisini ister (admin rolü "*" ile hepsine sahiptir)
+	requirePerm := customMiddleware.RequirePermission
 
-	// Admin-only endpoints
 	adminGroup := e.Group("/admin/users")
-	adminGroup.Use(customMiddleware.JWTMiddleware(cfg.JWTSecret))
-	adminGroup.Use(customMiddleware.AdminMiddleware)
+	adminGroup.Use(jwtAuth, denyImpersonation)
+
+	adminGroup.DELETE("/:id", userHandler.Delete, requirePerm(domain.PermUsersDelete), sameOrg)
+	adminGroup.PUT("/:id/role", userHandler.ChangeRole, requirePerm(domain.PermUsersManageRoles), sameOrg)
+	adminGroup.POST("/:id/unlock", userHandler.Unlock, requirePerm(domain.PermUsersUnlock), sameOrg) // Kilitlenen hesabı aç
+	adminGroup.GET("/:id/sessions", sessionHandler.ListUser, requirePerm(domain.PermUsersSessions), sameOrg)
+	adminGroup.DELETE("/:id/sessions", sessionHandler.RevokeUser, requirePerm(domain.PermUsersSessions), sameOrg)
+	adminGroup.GET("/:id/api-keys", apiKeyHandler.ListUser, denyAPIKey, requirePerm(domain.PermUsersAPIKeys), sameOrg)
+	adminGroup.POST("/:id/api-keys", apiKeyHandler.CreateUser, denyAPIKey, requirePerm(domain.PermUsersAPIKeys), sameOrg)
+	adminGroup.DELETE("/:id/api-keys/:keyId", apiKeyHandler.RevokeUser, denyAPIKey, requirePerm(domain.PermUsersAPIKeys), sameOrg)
+	// Destek: kullanıcının yerine geçme; API anahtarıyla başlatılamaz
+	adminGroup.POST("/:id/impersonate", impersonationHandler.Start, denyAPIKey, requirePerm(domain.PermUsersImpersonate), sameOrg)
+
+	// Silinmiş kullanıcılar: geri alma, kalıcı silme ve saklama süresi temizliği
+	adminGroup.GET("/deleted", retentionHandler.ListDeleted, requirePerm(domain.PermUsersDelete))
+	adminGroup.POST("/:id/restore", retentionHandler.Restore, requirePerm(domain.PermUsersDelete), sameOrg)
+	adminGroup.DELETE("/:id/purge", retentionHandler.Purge, requirePerm(domain.PermUsersDelete), sameOrg)
+	adminGroup.POST("/retention/run", retentionHandler.Run, superAdmin) // Tüm organizasyonları kapsar
+	adminGroup.GET("/retention/reports", retentionHandler.Reports, superAdmin)
+
+	// Adres defteri: kullanıcı kendi adreslerini, users:update yetkisi olanlar herkesinkini yönetir
+	addressGroup := userGroup.Group("/:id/addresses", customMiddleware.SelfOrPermission("id", domain.PermUsersUpdate), sameOrg)
+
+	addressGroup.GET("", addressHandler.List)
+	addressGroup.POST("", addressHandler.Create)
+	addressGroup.GET("/default", addressHandler.Default)
+	addressGroup.PUT("/:addressId", addressHandler.Update)
+	addressGroup.DELETE("/:addressId", addressHandler.Delete)
+	addressGroup.PUT("/:addressId/default", addressHandler.SetDefault)
+
+	// Kullanıcı ekleme davetle yapılır: şifreyi admin değil davetli belirler
+	invitationGroup := e.Group("/admin/invitations")
+	invitationGroup.Use(jwtAuth, denyImpersonation, requirePerm(domain.PermUsersCreate))
+	invitationGroup.POST("", invitationHandler.Invite)
+	invitationGroup.GET("", invitationHandler.List)
+	invitationGroup.DELETE("/:id", invitationHandler.Revoke)
+
+	privacyGroup := e.Group("/admin/privacy")
+	privacyGroup.Use(jwtAuth, denyImpersonation, requirePerm(domain.PermUsersDelete))
+	privacyGroup.GET("/erasures", privacyHandler.ListErasures)
+
+	impersonationGroup := e.Group("/admin/impersonations")
+	impersonationGroup.Use(jwtAuth, denyImpersonation, requirePerm(domain.PermUsersImpersonate))
+	impersonationGroup.GET("", impersonationHandler.List)
+	impersonationGroup.DELETE("/:id", impersonationHandler.End)
+
+	auditGroup := e.Group("/admin/audit")
+	auditGroup.Use(jwtAuth, denyImpersonation, requirePerm(domain.PermAuditRead))
+	auditGroup.GET("", auditHandler.List)
+	auditGroup.GET("/export", auditHandler.Export)
+
+	roleGroup := e.Group("/admin/roles")
+	roleGroup.Use(jwtAuth, denyImpersonation, requirePerm(domain.PermRolesManage))
+
+	roleGroup.GET("", roleHandler.List)
+	roleGroup.GET("/permissions", roleHandler.Permissions)
+	roleGroup.GET("/:name", roleHandler.Get)
+	// Roller tüm organizasyonlarda ortak olduğu için sadece süper admin değiştirebilir
+	roleGroup.POST("", roleHandler.Create, superAdmin)
+	roleGroup.PUT("/:name", roleHandler.Update, superAdmin)
+	roleGroup.DELETE("/:name", roleHandler.Delete, superAdmin)
+
+	orgGroup := e.Group("/admin/organizations")
+	orgGroup.Use(jwtAuth, denyImpersonation, superAdmin)
+	orgGroup.GET("", organizationHandler.List)
+	orgGroup.POST("", organizationHandler.Create)
+	orgGroup.GET("/:id", organizationHandler.Get)
+	orgGroup.PUT("/:id", organizationHandler.Update)
 
-	adminGroup.POST("", userHandler.Create)             // Sadece admin yeni user ekleyebilir
-	adminGroup.DELETE("/:id", userHandler.Delete)       // Sadece admin user silebilir
-	adminGroup.PUT("/:id/role", userHandler.ChangeRole) // Sadece admin role değiştirebilir
+	// OIDC istemcileri tüm organizasyonların kullanıcılarına giriş açtığı için sadece süper admin yönetir
+	oauthClientGroup := e.Group("/admin/oauth/clients")
+	oauthClientGroup.Use(jwtAuth, denyImpersonation, superAdmin)
+	oauthClientGroup.GET("", oauthClientHandler.List)
+	oauthClientGroup.POST("", oauthClientHandler.Register)
+	oauthClientGroup.GET("/:id", oauthClientHandler.Get)
+	oauthClientGroup.DELETE("/:id", oauthClientHandler.Delete)
 
 	log.Printf("Server running on port %s", cfg.Port)
 	e.Logger.Fatal(e.Start(":" + cfg.Port))
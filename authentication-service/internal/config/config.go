@@ -1,22 +1,155 @@
 package config
 
 import (
+	"authentication-service/internal/domain"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	MongoURI  string
-	DbName    string
-	Port      string
-	JWTSecret string 
+	MongoURI            string
+	DbName              string
+	AuditDbName         string // İki servisin ortak denetim kayıtları veritabanı
+	Port                string
+	AppEnv              string // development, staging, production
+	FixturesEnabled     bool   // Demo verileri yükle (production'da her zaman reddedilir)
+	FixturesFile        string
+	JWTSigningAlg       string        // RS256 veya EdDSA
+	KeyRotationInterval time.Duration // İmza anahtarının değiştirilme sıklığı
+	KeyRetention        time.Duration // Eski public key'lerin JWKS'de kalma süresi
+	AccessTokenTTL      time.Duration // Kısa ömürlü access token süresi
+	RefreshTokenTTL     time.Duration // Refresh token (oturum) süresi
+	PasswordResetTTL    time.Duration // Şifre sıfırlama linkinin geçerlilik süresi
+	VerificationTTL     time.Duration // E-posta doğrulama linkinin geçerlilik süresi
+	InvitationTTL       time.Duration // Admin davet linkinin geçerlilik süresi
+
+	// X-Forwarded-For'una güvenilen reverse proxy ağları (CIDR). Boşsa istemci IP'si bağlantıdan alınır.
+	TrustedProxies []string
+
+	// "allow": doğrulanmamış kullanıcılar giriş yapabilir (token'da email_verified=false olur)
+	// "deny": e-posta doğrulanana kadar giriş engellenir
+	UnverifiedLoginPolicy string
+
+	// Brute-force koruması
+	LoginFreeAttempts    int           // Bekleme uygulanmadan izin verilen hatalı deneme
+	LoginMaxAttempts     int           // Hesabın kilitlendiği hatalı deneme sayısı
+	LoginIPFreeAttempts  int           // IP başına beklemesiz hatalı deneme
+	LoginIPMaxAttempts   int           // IP'nin kilitlendiği hatalı deneme sayısı
+	LoginBackoffBase     time.Duration // İlk bekleme süresi, her hatada iki katına çıkar
+	LoginBackoffMax      time.Duration
+	LoginLockoutDuration time.Duration
+	LoginFailureWindow   time.Duration // Bu süre hatasız geçerse sayaç sıfırlanır
+
+	// İki adımlı doğrulama
+	MFAIssuer        string        // Authenticator uygulamasında görünen isim
+	MFARequiredRoles []string      // MFA'nın zorunlu olduğu roller
+	MFAChallengeTTL  time.Duration // Şifre adımından sonra kodun girilmesi için verilen süre
+
+	// Silinen kullanıcıların saklanması
+	UserRetentionPeriod   time.Duration // Soft-delete edilmiş kullanıcı bu süreden sonra kalıcı silinir (0: kapalı)
+	UserRetentionInterval time.Duration // Temizlik işinin çalışma sıklığı
+
+	// OpenID Connect sağlayıcı modu
+	OIDCIssuer  string        // Token'lardaki "iss"; servisin dışarıdan erişilen adresi olmalı
+	OIDCCodeTTL time.Duration // Yetkilendirme kodunun token'a çevrilmesi için süre
+
+	// Kiosk ve entegrasyonlar için kişisel API anahtarları
+	APIKeyDefaultTTL time.Duration
+	APIKeyMaxTTL     time.Duration
+	APIKeyMaxPerUser int
+
+	// Destek personelinin kullanıcı yerine geçtiği oturumların ömrü; refresh ile uzatılamaz
+	ImpersonationTTL time.Duration
+
+	// Waste servisinin client_credentials kimliği; secret boşsa istemci açılışta oluşturulmaz
+	ServiceClientID     string
+	ServiceClientSecret string
+
+	// Auth servisinin waste'e KVKK/GDPR isteklerinde kullandığı ayrı kimlik; sadece privacy scope'larını alır.
+	// Secret waste servisiyle paylaşılmaz; boşsa dışa aktarma ve silme çalışmaz.
+	PrivacyClientID     string
+	PrivacyClientSecret string
+
+	// KVKK/GDPR dışa aktarma ve silme için waste servisine yapılan istekler
+	WasteServiceURL     string
+	WasteServiceTimeout time.Duration
+
+	AppBaseURL    string // E-postalardaki linklerin açılacağı frontend adresi
+	MailDriver    string // "smtp" veya "outbox"
+	MailFrom      string
+	MailOutboxDir string
+	SMTPHost      string
+	SMTPPort      string
+	SMTPUsername  string
+	SMTPPassword  string
 }
 
 func LoadConfig() *Config {
 	return &Config{
-		MongoURI:  getEnv("MONGO_URI", "mongodb+srv://tesodevmongodb:<db_password>@cluster0.ajddxq7.mongodb.net/?appName=Cluster0"),
-		DbName:    getEnv("DB_NAME", "auth_db"),
-		Port:      getEnv("PORT", "8080"),
-		JWTSecret: getEnv("JWT_SECRET", "super_secret_key_change_me"), 
+		MongoURI:            getEnv("MONGO_URI", "mongodb+srv://tesodevmongodb:<db_password>@cluster0.ajddxq7.mongodb.net/?appName=Cluster0"),
+		DbName:              getEnv("DB_NAME", "auth_db"),
+		AuditDbName:         getEnv("AUDIT_DB_NAME", "audit_db"),
+		Port:                getEnv("PORT", "8080"),
+		AppEnv:              getEnv("APP_ENV", "development"),
+		FixturesEnabled:     getBool("FIXTURES_ENABLED", false),
+		FixturesFile:        getEnv("FIXTURES_FILE", "./fixtures/users.yaml"),
+		JWTSigningAlg:       getEnv("JWT_SIGNING_ALG", "RS256"),
+		KeyRotationInterval: getDuration("KEY_ROTATION_INTERVAL", 30*24*time.Hour),
+		KeyRetention:        getDuration("KEY_RETENTION", 24*time.Hour),
+		AccessTokenTTL:      getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:     getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		PasswordResetTTL:    getDuration("PASSWORD_RESET_TTL", time.Hour),
+		VerificationTTL:     getDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+		InvitationTTL:       getDuration("INVITATION_TTL", 7*24*time.Hour),
+
+		TrustedProxies: getList("TRUSTED_PROXIES", nil),
+
+		UnverifiedLoginPolicy: getEnv("UNVERIFIED_LOGIN_POLICY", "allow"),
+
+		LoginFreeAttempts:    getInt("LOGIN_FREE_ATTEMPTS", 3),
+		LoginMaxAttempts:     getInt("LOGIN_MAX_ATTEMPTS", 10),
+		LoginIPFreeAttempts:  getInt("LOGIN_IP_FREE_ATTEMPTS", 20),
+		LoginIPMaxAttempts:   getInt("LOGIN_IP_MAX_ATTEMPTS", 100),
+		LoginBackoffBase:     getDuration("LOGIN_BACKOFF_BASE", time.Second),
+		LoginBackoffMax:      getDuration("LOGIN_BACKOFF_MAX", 5*time.Minute),
+		LoginLockoutDuration: getDuration("LOGIN_LOCKOUT_DURATION", 30*time.Minute),
+		LoginFailureWindow:   getDuration("LOGIN_FAILURE_WINDOW", time.Hour),
+
+		MFAIssuer:        getEnv("MFA_ISSUER", "AdvancedKTU"),
+		MFARequiredRoles: getList("MFA_REQUIRED_ROLES", []string{domain.RoleAdmin, domain.RoleSuperAdmin}),
+		MFAChallengeTTL:  getDuration("MFA_CHALLENGE_TTL", 5*time.Minute),
+
+		UserRetentionPeriod:   getDuration("USER_RETENTION_PERIOD", 90*24*time.Hour),
+		UserRetentionInterval: getDuration("USER_RETENTION_INTERVAL", 24*time.Hour),
+
+		OIDCIssuer:  strings.TrimSuffix(getEnv("OIDC_ISSUER", "http://localhost:8080"), "/"),
+		OIDCCodeTTL: getDuration("OIDC_CODE_TTL", time.Minute),
+
+		APIKeyDefaultTTL: getDuration("API_KEY_DEFAULT_TTL", 90*24*time.Hour),
+		APIKeyMaxTTL:     getDuration("API_KEY_MAX_TTL", 365*24*time.Hour),
+		APIKeyMaxPerUser: getInt("API_KEY_MAX_PER_USER", 10),
+
+		ImpersonationTTL: getDuration("IMPERSONATION_TTL", 15*time.Minute),
+
+		ServiceClientID:     getEnv("SERVICE_CLIENT_ID", "waste-service"),
+		ServiceClientSecret: getEnv("SERVICE_CLIENT_SECRET", ""),
+
+		PrivacyClientID:     getEnv("PRIVACY_CLIENT_ID", "auth-privacy"),
+		PrivacyClientSecret: getEnv("PRIVACY_CLIENT_SECRET", ""),
+
+		WasteServiceURL:     getEnv("WASTE_SERVICE_URL", "http://localhost:8081"),
+		WasteServiceTimeout: getDuration("WASTE_SERVICE_TIMEOUT", time.Minute),
+
+		AppBaseURL:    getEnv("APP_BASE_URL", "http://localhost:5173"),
+		MailDriver:    getEnv("MAIL_DRIVER", "outbox"),
+		MailFrom:      getEnv("MAIL_FROM", "no-reply@advancedktu.local"),
+		MailOutboxDir: getEnv("MAIL_OUTBOX_DIR", "./outbox"),
+		SMTPHost:      getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:      getEnv("SMTP_PORT", "587"),
+		SMTPUsername:  getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
 	}
 }
 
@@ -25,4 +158,47 @@ func getEnv(key, fallback string) string {
 		return value
 	}
 	return fallback
-}
\ No newline at end of file
+}
+
+// getDuration "15m", "720h" gibi değerleri okur, hatalıysa fallback döner
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func getBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// getList virgülle ayrılmış değerleri okur ("admin,collector"). Boş değer boş liste demektir.
+func getList(key string, fallback []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
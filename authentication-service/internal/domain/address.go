@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAddressNotFound = errors.New("address not found")
+	ErrLastAddress     = errors.New("at least one address is required")
+	ErrInvalidAddress  = errors.New("full_address and city are required")
+	ErrLocationUnknown = errors.New("location could not be geocoded")
+)
+
+// Geocoder il/ilçe bilgisini koordinata çevirir. Bulunamazsa ErrLocationUnknown döner.
+type Geocoder interface {
+	Geocode(ctx context.Context, city, district string) (lat, lng float64, err error)
+}
+
+// HasCoordinates adresin koordinatı girilmiş mi
+func (a *Address) HasCoordinates() bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
+
+// PrepareAddresses ID'si olmayan adreslere ID verir ve tam olarak bir varsayılan adres bırakır
+// (hiç işaretlenmemişse ilki, birden fazla işaretlenmişse ilk işaretlenen).
+func PrepareAddresses(addresses []Address) []Address {
+	defaultSet := false
+	for i := range addresses {
+		if addresses[i].ID.IsZero() {
+			addresses[i].ID = primitive.NewObjectID()
+		}
+		if addresses[i].IsDefault && !defaultSet {
+			defaultSet = true
+			continue
+		}
+		addresses[i].IsDefault = false
+	}
+	if !defaultSet && len(addresses) > 0 {
+		addresses[0].IsDefault = true
+	}
+	return addresses
+}
+
+// DefaultAddress varsayılan adresi döner; işaretli adres yoksa ilkini
+func (u *User) DefaultAddress() *Address {
+	for i := range u.Addresses {
+		if u.Addresses[i].IsDefault {
+			return &u.Addresses[i]
+		}
+	}
+	if len(u.Addresses) > 0 {
+		return &u.Addresses[0]
+	}
+	return nil
+}
+
+type AddressService interface {
+	List(ctx context.Context, userID string) ([]Address, error)
+	Default(ctx context.Context, userID string) (*Address, error)
+	Add(ctx context.Context, userID string, address *Address) error
+	Update(ctx context.Context, userID, addressID string, address *Address) error
+	Delete(ctx context.Context, userID, addressID string) error
+	SetDefault(ctx context.Context, userID, addressID string) error
+}
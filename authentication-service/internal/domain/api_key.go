@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyPrefix anahtarların başındaki sabit; loglarda ve secret taramalarında tanınsın diye
+const APIKeyPrefix = "ak_"
+
+var (
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrInvalidAPIKey       = errors.New("invalid, expired or revoked api key")
+	ErrInvalidAPIKeyName   = errors.New("api key name is required")
+	ErrInvalidAPIKeyScopes = errors.New("api key needs at least one scope")
+	ErrAPIKeyScope         = errors.New("scope is not held by the key owner")
+	ErrInvalidAPIKeyExpiry = errors.New("invalid api key expiry")
+	ErrAPIKeyLimit         = errors.New("api key limit reached")
+	ErrAPIKeyNotAllowed    = errors.New("this endpoint requires an interactive login")
+)
+
+// APIKey kiosk ve entegrasyonlar için uzun ömürlü kimlik bilgisi. Anahtar "ak_<prefix>_<secret>"
+// biçimindedir; sadece oluşturulurken bir kez gösterilir, veritabanında secret'ın hash'i tutulur.
+// Anahtar sahibinin yetkilerinden sadece Scopes'ta seçilenleri taşır.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	OrgID      string             `bson:"org_id" json:"org_id"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // Anahtarı listede tanımak ve aramak için, gizli değildir
+	SecretHash string             `bson:"secret_hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	CreatedBy  string             `bson:"created_by" json:"created_by"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// APIKeyIdentity anahtarla yapılan isteğin kimliği. Yetkiler anahtarın scope'ları ile
+// sahibinin rolünün o anki yetkilerinin kesişimidir; rol değişiklikleri hemen yansır.
+type APIKeyIdentity struct {
+	KeyID       string
+	UserID      string
+	Email       string
+	Role        string // Süper admin anahtarlarında boştur: anahtarlar organizasyon sınırını aşamaz
+	OrgID       string
+	Permissions []string
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id string) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	// ListByUser kullanıcının iptal edilmemiş anahtarlarını yeniden eskiye döner
+	ListByUser(ctx context.Context, userID string) ([]*APIKey, error)
+	// Revoke anahtar iptal edilmemişse iptal eder ve true döner
+	Revoke(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// DeleteByUser kullanıcının iptal edilmiş olanlar dahil tüm anahtarlarını kalıcı siler
+	DeleteByUser(ctx context.Context, userID string) error
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID) error
+}
+
+type APIKeyService interface {
+	// Create anahtarı kaydeder ve düz metin anahtarı döner; bu değer bir daha görüntülenemez
+	Create(ctx context.Context, key *APIKey, ttl time.Duration) (string, error)
+	List(ctx context.Context, userID string) ([]*APIKey, error)
+	// Revoke userID'ye ait anahtarı iptal eder; başkasının anahtarı için ErrAPIKeyNotFound
+	Revoke(ctx context.Context, userID, id string) error
+	// Authenticate X-API-Key değerini doğrular
+	Authenticate(ctx context.Context, key string) (*APIKeyIdentity, error)
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit olayları; waste servisi de aynı audit_db.audit_events koleksiyonuna yazar
+const (
+	AuditLogin              = "auth.login"
+	AuditLoginFailed        = "auth.login_failed"
+	AuditRegister           = "auth.register"
+	AuditPasswordChange     = "auth.password_change"
+	AuditPasswordReset      = "auth.password_reset"
+	AuditMFAEnable          = "auth.mfa_enable"
+	AuditMFADisable         = "auth.mfa_disable"
+	AuditUserCreate         = "user.create"
+	AuditUserUpdate         = "user.update"
+	AuditUserDelete         = "user.delete"
+	AuditUserRoleChange     = "user.role_change"
+	AuditUserUnlock         = "user.unlock"
+	AuditUserRestore        = "user.restore"
+	AuditUserPurge          = "user.purge"
+	AuditRetentionRun       = "user.retention_run"
+	AuditRoleCreate         = "role.create"
+	AuditRoleUpdate         = "role.update"
+	AuditRoleDelete         = "role.delete"
+	AuditPrivacyExport      = "privacy.export"
+	AuditPrivacyErase       = "privacy.erase"
+	AuditSessionRevoke      = "session.revoke"
+	AuditSessionRevokeAll   = "session.revoke_all"
+	AuditInvitationCreate   = "invitation.create"
+	AuditInvitationRevoke   = "invitation.revoke"
+	AuditInvitationAccept   = "invitation.accept"
+	AuditOrganizationCreate = "organization.create"
+	AuditOrganizationUpdate = "organization.update"
+	AuditOAuthClientCreate  = "oauth_client.create"
+	AuditOAuthClientDelete  = "oauth_client.delete"
+	AuditAPIKeyCreate       = "api_key.create"
+	AuditAPIKeyRevoke       = "api_key.revoke"
+	AuditImpersonationStart = "impersonation.start"
+	AuditImpersonationEnd   = "impersonation.end"
+)
+
+const (
+	AuditServiceName = "authentication-service"
+
+	AuditTargetUser         = "user"
+	AuditTargetRole         = "role"
+	AuditTargetRetention    = "retention"
+	AuditTargetSession      = "session"
+	AuditTargetInvitation   = "invitation"
+	AuditTargetOrganization = "organization"
+	AuditTargetOAuthClient  = "oauth_client"
+	AuditTargetAPIKey       = "api_key"
+)
+
+var ErrInvalidAuditFormat = errors.New("format must be csv or jsonl")
+
+// AuditEvent değiştirilemez denetim kaydı. Before/After sadece değişen alanları içerir.
+type AuditEvent struct {
+	ID                primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Service           string                 `bson:"service" json:"service"`
+	OrgID             string                 `bson:"org_id,omitempty" json:"org_id,omitempty"` // Olayın ait olduğu organizasyon; organizasyon admin'leri sadece bunları görür
+	Action            string                 `bson:"action" json:"action"`
+	ActorID           string                 `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	ActorEmail        string                 `bson:"actor_email,omitempty" json:"actor_email,omitempty"`
+	ImpersonatorID    string                 `bson:"impersonator_id,omitempty" json:"impersonator_id,omitempty"` // Impersonation sırasında actor taklit edilen kullanıcı, bu ise yerine geçen kişidir
+	ImpersonatorEmail string                 `bson:"impersonator_email,omitempty" json:"impersonator_email,omitempty"`
+	TargetType        string                 `bson:"target_type,omitempty" json:"target_type,omitempty"`
+	TargetID          string                 `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	Before            map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After             map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`
+	Metadata          map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	IP                string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent         string                 `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	CreatedAt         time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// AuditFilter listeleme ve dışa aktarma filtreleri; boş alanlar filtre uygulanmaz demektir
+type AuditFilter struct {
+	Service        string
+	OrgID          string
+	Action         string // "user.*" gibi önek de kabul edilir
+	ActorID        string
+	ImpersonatorID string // Bu kişinin impersonation sırasında yaptığı işlemler
+	TargetType     string
+	TargetID       string
+	From           *time.Time
+	To             *time.Time
+	Page           int
+	Limit          int
+}
+
+type AuditPage struct {
+	Items []*AuditEvent `json:"items"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+// AuditRepository sadece ekleme ve okuma yapar; kayıtlar güncellenmez ve silinmez
+type AuditRepository interface {
+	Append(ctx context.Context, event *AuditEvent) error
+	List(ctx context.Context, filter AuditFilter) ([]*AuditEvent, int64, error)
+	// Stream filtreye uyan tüm kayıtları eskiden yeniye fn'e verir (dışa aktarma için)
+	Stream(ctx context.Context, filter AuditFilter, fn func(*AuditEvent) error) error
+}
+
+// AuditLogger servislerin olay kaydetmek için kullandığı arayüz. Kayıt hatası işlemi durdurmaz.
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+type AuditService interface {
+	AuditLogger
+	List(ctx context.Context, filter AuditFilter) (*AuditPage, error)
+	// Export kayıtları "csv" veya "jsonl" formatında yazar
+	Export(ctx context.Context, filter AuditFilter, format string, w io.Writer) error
+}
+
+// AuditActor isteği yapan kişi ve istemci; middleware'ler request context'ine koyar
+type AuditActor struct {
+	UserID    string
+	Email     string
+	OrgID     string
+	IP        string
+	UserAgent string
+	// Impersonation token'ındaki "act" claim'i
+	ImpersonatorID    string
+	ImpersonatorEmail string
+}
+
+type auditActorKey struct{}
+
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrImpersonationNotAllowed = errors.New("this user cannot be impersonated")
+	ErrImpersonationReason     = errors.New("a reason is required to impersonate a user")
+	ErrImpersonating           = errors.New("this action is not allowed while impersonating")
+	ErrNotImpersonating        = errors.New("token is not an impersonation token")
+	ErrImpersonationNotFound   = errors.New("impersonation session not found")
+)
+
+// Actor token'daki "act" claim'i (RFC 8693): kullanıcının yerine işlem yapan destek personeli
+type Actor struct {
+	Sub   string `json:"sub"`
+	Email string `json:"email,omitempty"`
+}
+
+// ImpersonationRequest destek personelinin kullanıcının gözünden bakmak için açtığı oturum isteği
+type ImpersonationRequest struct {
+	Actor       Actor
+	Permissions []string // İsteği yapanın yetkileri; hedefin yetkileri bunların içinde olmalı
+	UserID      string
+	Reason      string
+}
+
+// ImpersonationResult kısa ömürlü, refresh edilemeyen access token
+type ImpersonationResult struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresIn   int64     `json:"expires_in"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	SessionID   string    `json:"session_id"`
+	User        *User     `json:"user"`
+}
+
+type ImpersonationFilter struct {
+	OrgID          string // Boşsa tüm organizasyonlar (sadece süper admin)
+	UserID         string
+	ImpersonatorID string
+	ActiveOnly     bool
+	Page           int
+	Limit          int
+}
+
+type ImpersonationPage struct {
+	Items []*Session `json:"items"`
+	Total int64      `json:"total"`
+	Page  int        `json:"page"`
+	Limit int        `json:"limit"`
+}
+
+type ImpersonationService interface {
+	// Start hedef kullanıcı adına oturum açar. Süper admin'ler, kişinin kendisi ve isteği
+	// yapandan fazla yetkisi olan kullanıcılar taklit edilemez.
+	Start(ctx context.Context, req ImpersonationRequest, client ClientInfo) (*ImpersonationResult, error)
+	List(ctx context.Context, filter ImpersonationFilter) (*ImpersonationPage, error)
+	// End oturumu kapatır; orgID boş değilse sadece o organizasyondaki oturumlar kapatılabilir
+	End(ctx context.Context, sessionID, orgID string) error
+}
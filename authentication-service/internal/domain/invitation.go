@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationRevoked  = "revoked"
+	InvitationExpired  = "expired" // Saklanmaz; süresi geçmiş bekleyen davetler için okurken hesaplanır
+)
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvalidInvitation  = errors.New("invalid or expired invitation")
+	ErrInvitationExists   = errors.New("a pending invitation already exists for this email")
+	ErrInvitationClosed   = errors.New("invitation is no longer pending")
+)
+
+// Invitation admin'in e-posta ile gönderdiği, rolü önceden belirlenmiş davet.
+// Davetli linkle şifresini ve adreslerini kendisi belirleyerek hesabını açar.
+type Invitation struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email      string             `bson:"email" json:"email"`
+	Role       string             `bson:"role" json:"role"`
+	OrgID      string             `bson:"org_id" json:"org_id"` // Hesabın açılacağı organizasyon
+	FirstName  string             `bson:"first_name,omitempty" json:"first_name,omitempty"`
+	LastName   string             `bson:"last_name,omitempty" json:"last_name,omitempty"`
+	Status     string             `bson:"status" json:"status"`
+	TokenHash  string             `bson:"token_hash" json:"-"`
+	InvitedBy  string             `bson:"invited_by" json:"invited_by"`
+	UserID     string             `bson:"user_id,omitempty" json:"user_id,omitempty"` // Kabul edilince oluşan hesap
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	AcceptedAt *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// InvitationPreview davet linkini açan kişiye gösterilen bilgiler
+type InvitationPreview struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	FirstName string    `json:"first_name,omitempty"`
+	LastName  string    `json:"last_name,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// InvitationAcceptance davetlinin hesabını açarken gönderdiği bilgiler
+type InvitationAcceptance struct {
+	Password  string
+	FirstName string
+	LastName  string
+	Addresses []Address
+}
+
+type InvitationFilter struct {
+	Status string // pending, accepted, revoked, expired
+	OrgID  string // Boşsa tüm organizasyonlar (sadece süper admin)
+	Page   int
+	Limit  int
+}
+
+type InvitationPage struct {
+	Items []*Invitation `json:"items"`
+	Total int64         `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *Invitation) error
+	GetByID(ctx context.Context, id string) (*Invitation, error)
+	GetByTokenHash(ctx context.Context, hash string) (*Invitation, error)
+	// GetPendingByEmail süresi dolmamış bekleyen daveti döner
+	GetPendingByEmail(ctx context.Context, email string) (*Invitation, error)
+	List(ctx context.Context, filter InvitationFilter) ([]*Invitation, int64, error)
+	// MarkAccepted davet hala bekliyorsa kabul edildi olarak işaretler ve true döner
+	MarkAccepted(ctx context.Context, id primitive.ObjectID, userID string) (bool, error)
+	// Revoke davet hala bekliyorsa iptal eder ve true döner
+	Revoke(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// DeleteByUser kullanıcıya gönderilen davetleri siler, gönderdiği davetlerden de kimliğini kaldırır
+	DeleteByUser(ctx context.Context, userID, email string) error
+}
+
+type InvitationService interface {
+	// Invite daveti kaydeder ve tek kullanımlık linki e-posta ile gönderir
+	Invite(ctx context.Context, invitation *Invitation) error
+	List(ctx context.Context, filter InvitationFilter) (*InvitationPage, error)
+	// Revoke orgID boş değilse sadece o organizasyonun davetini iptal eder
+	Revoke(ctx context.Context, id, orgID string) error
+	Preview(ctx context.Context, token string) (*InvitationPreview, error)
+	// Accept hesabı davetteki e-posta ve rolle açar; e-posta link ile doğrulanmış sayılır
+	Accept(ctx context.Context, token string, acceptance InvitationAcceptance) (*User, error)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey JWT imzalamada kullanılan asimetrik anahtar çifti.
+// Emekliye ayrılan (retired) anahtar artık imza atmaz ama ExpiresAt'e kadar
+// JWKS'de yayınlanmaya devam eder; böylece onunla imzalanmış token'lar doğrulanabilir.
+type SigningKey struct {
+	ID         string     `bson:"_id" json:"kid"`
+	Algorithm  string     `bson:"algorithm" json:"alg"` // RS256 veya EdDSA
+	PrivateKey string     `bson:"private_key" json:"-"` // PKCS#8 PEM
+	PublicKey  string     `bson:"public_key" json:"-"`  // PKIX PEM
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	RetiredAt  *time.Time `bson:"retired_at,omitempty" json:"retired_at,omitempty"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+}
+
+// JWK RFC 7517 public key gösterimi
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP eğrisi (Ed25519)
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type KeyRepository interface {
+	CreateKey(ctx context.Context, key *SigningKey) error
+	ListKeys(ctx context.Context) ([]*SigningKey, error)
+	// RetireKeysExcept verilen anahtar dışındaki aktif anahtarları emekliye ayırır
+	RetireKeysExcept(ctx context.Context, id string, retiredAt, expiresAt time.Time) error
+	DeleteExpiredKeys(ctx context.Context, now time.Time) error
+}
+
+type KeyService interface {
+	// Sign claim'leri aktif anahtarla imzalar ve "kid" header'ını ekler
+	Sign(claims jwt.Claims) (string, error)
+	// Keyfunc jwt.Parse için "kid"e göre public key döner
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	ValidMethods() []string
+	JWKS() *JWKSet
+	// Refresh anahtarları veritabanından yükler, gerekiyorsa rotasyon yapar
+	Refresh(ctx context.Context) error
+	// Start periyodik yenileme/rotasyon döngüsünü başlatır
+	Start(ctx context.Context)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+// MailMessage gönderilecek düz metin e-posta
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer e-posta gönderim soyutlaması. Prod'da SMTP, geliştirme ve testte
+// mesajları diske yazan outbox implementasyonu kullanılır.
+type Mailer interface {
+	Send(ctx context.Context, msg MailMessage) error
+}
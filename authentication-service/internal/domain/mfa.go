@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidMFACode      = errors.New("invalid mfa code")
+	ErrInvalidMFAToken     = errors.New("invalid or expired mfa token")
+	ErrMFANotEnrolled      = errors.New("mfa enrollment has not been started")
+	ErrMFAAlreadyEnabled   = errors.New("mfa is already enabled")
+	ErrMFANotEnabled       = errors.New("mfa is not enabled")
+	ErrMFARequiredForRole  = errors.New("mfa is mandatory for this role")
+	ErrMFAEnrollmentNeeded = errors.New("mfa enrollment required before login")
+)
+
+// MFAEnrollment authenticator uygulamasına eklenecek secret ve otpauth:// adresi
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// LoginResult şifre doğrulandıktan sonra ya token'ları ya da MFA challenge'ını taşır
+type LoginResult struct {
+	Tokens                *TokenPair
+	User                  *User
+	MFARequired           bool
+	MFAEnrollmentRequired bool     // Rol MFA zorunlu ama kullanıcı henüz kurulum yapmamış
+	MFAToken              string   // /auth/login/mfa ile takas edilecek kısa ömürlü token
+	RecoveryCodes         []string // Login sırasında MFA kurulumu tamamlandıysa bir kez gösterilir
+}
+
+type MFAService interface {
+	// IsRequired kullanıcının rolü için MFA zorunlu mu
+	IsRequired(user *User) bool
+	BeginEnrollment(ctx context.Context, userID string) (*MFAEnrollment, error)
+	// ActivateEnrollment bekleyen secret'ı kod ile doğrular, MFA'yı açar ve kurtarma kodlarını döner
+	ActivateEnrollment(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	// VerifyLogin TOTP kodunu veya tek kullanımlık kurtarma kodunu doğrular
+	VerifyLogin(ctx context.Context, user *User, code, recoveryCode string) error
+}
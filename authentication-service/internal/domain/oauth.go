@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenID Connect scope'ları. "openid" zorunludur; refresh token sadece "offline_access" istenirse verilir.
+const (
+	ScopeOpenID        = "openid"
+	ScopeProfile       = "profile"
+	ScopeEmail         = "email"
+	ScopeOfflineAccess = "offline_access"
+
+	CodeChallengeS256 = "S256"
+
+	GrantAuthorizationCode = "authorization_code"
+	GrantRefreshToken      = "refresh_token"
+	GrantClientCredentials = "client_credentials"
+)
+
+// Servis scope'ları: makine istemcileri client_credentials ile kendi adlarına token alır
+const (
+	ScopeTokenIntrospect = "token:introspect" // /oauth/introspect ile token sorgulama
+	ScopeSessionsRevoked = "sessions:revoked" // İptal edilen oturum akışını okuma
+	ScopePrivacyExport   = "privacy:export"   // Waste servisinden kullanıcının verisini dışa aktarma
+	ScopePrivacyErase    = "privacy:erase"    // Waste servisindeki kullanıcı verisini silme
+)
+
+// OIDCScopes istemcilerin isteyebileceği scope'lar
+var OIDCScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail, ScopeOfflineAccess}
+
+// ServiceScopes makine istemcilerine verilebilecek scope'lar
+var ServiceScopes = []string{ScopeTokenIntrospect, ScopeSessionsRevoked, ScopePrivacyExport, ScopePrivacyErase}
+
+// Açılışta oluşturulan istemcilerin scope'ları; her çağıran sadece kendi işinin scope'unu alır
+var (
+	WasteServiceScopes = []string{ScopeTokenIntrospect, ScopeSessionsRevoked} // Token doğrulama ve iptal akışı
+	PrivacyScopes      = []string{ScopePrivacyExport, ScopePrivacyErase}      // Auth servisinin waste'e KVKK/GDPR istekleri
+)
+
+var (
+	ErrOAuthClientNotFound = errors.New("oauth client not found")
+	ErrInvalidRedirectURI  = errors.New("redirect uri must be an absolute https url (http is allowed only for localhost)")
+	ErrCodeNotFound        = errors.New("authorization code not found")
+	ErrInvalidServiceScope = errors.New("unknown service scope")
+	ErrInvalidClientToken  = errors.New("invalid or expired client token")
+	ErrInsufficientScope   = errors.New("insufficient scope")
+)
+
+// RFC 6749 5.2 hata kodları
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthAccessDenied            = "access_denied"
+	OAuthServerError             = "server_error"
+)
+
+// OAuthError istemciye {"error", "error_description"} olarak dönen standart hata
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// OAuthClient şifre görmeden kullanıcı adına token alabilen uygulama (admin paneli, partner portalı)
+// veya kendi adına token alan servis (waste-service, chatBot). Public istemciler (SPA, mobil)
+// secret tutamaz; kod değişimi sadece PKCE ile korunur.
+type OAuthClient struct {
+	ID           string    `bson:"_id" json:"client_id"`
+	Name         string    `bson:"name" json:"name"`
+	SecretHash   string    `bson:"secret_hash,omitempty" json:"-"`
+	RedirectURIs []string  `bson:"redirect_uris" json:"redirect_uris"`       // Birebir eşleşme aranır; servis istemcilerinde boş olabilir
+	Scopes       []string  `bson:"scopes,omitempty" json:"scopes,omitempty"` // client_credentials ile alınabilecek servis scope'ları
+	Public       bool      `bson:"public" json:"public"`
+	FirstParty   bool      `bson:"first_party" json:"first_party"` // Kendi uygulamalarımız; üçüncü taraf token'ları rol ve yetki taşımaz
+	CreatedBy    string    `bson:"created_by" json:"created_by"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+// AuthorizationCode kullanıcı giriş yaptıktan sonra redirect_uri'ye gönderilen tek kullanımlık kod
+type AuthorizationCode struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty"`
+	CodeHash      string              `bson:"code_hash"`
+	ClientID      string              `bson:"client_id"`
+	UserID        string              `bson:"user_id"`
+	RedirectURI   string              `bson:"redirect_uri"`
+	Scopes        []string            `bson:"scopes"`
+	Nonce         string              `bson:"nonce,omitempty"`
+	CodeChallenge string              `bson:"code_challenge"`
+	AMR           []string            `bson:"amr"`
+	AuthTime      time.Time           `bson:"auth_time"`
+	ExpiresAt     time.Time           `bson:"expires_at"`
+	UsedAt        *time.Time          `bson:"used_at,omitempty"`
+	SessionID     *primitive.ObjectID `bson:"session_id,omitempty"` // Kod tekrar kullanılırsa bu oturum kapatılır
+}
+
+// AuthorizationRequest /oauth/authorize parametreleri
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// TokenRequest /oauth/token parametreleri; istemci kimliği Basic header'dan veya gövdeden gelir
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string // client_credentials: boşsa istemcinin tüm servis scope'ları
+	ClientID     string
+	ClientSecret string
+}
+
+type OIDCTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OIDCDiscovery /.well-known/openid-configuration dokümanı
+type OIDCDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// TokenIntrospection RFC 7662 cevabı. Token geçersiz, süresi dolmuş veya oturumu iptal
+// edilmişse sadece active=false döner. Rol, yetki ve organizasyon bu servise özgü alanlardır.
+type TokenIntrospection struct {
+	Active      bool     `json:"active"`
+	Scope       string   `json:"scope,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	Username    string   `json:"username,omitempty"`
+	TokenType   string   `json:"token_type,omitempty"`
+	Exp         int64    `json:"exp,omitempty"`
+	Iat         int64    `json:"iat,omitempty"`
+	Sub         string   `json:"sub,omitempty"` // Kullanıcı token'larında user_id, servis token'larında client_id
+	Iss         string   `json:"iss,omitempty"`
+	Kind        string   `json:"typ,omitempty"` // access, delegated, client veya api_key
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	OrgID       string   `json:"org,omitempty"`
+	SessionID   string   `json:"sid,omitempty"`
+	Act         *Actor   `json:"act,omitempty"` // Impersonation token'ında kullanıcının yerine geçen kişi
+}
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *OAuthClient) error
+	// Upsert sabit kimlikli istemciyi oluşturur ya da secret, scope ve adını günceller
+	Upsert(ctx context.Context, client *OAuthClient) error
+	Get(ctx context.Context, id string) (*OAuthClient, error)
+	List(ctx context.Context) ([]*OAuthClient, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *AuthorizationCode) error
+	GetByHash(ctx context.Context, hash string) (*AuthorizationCode, error)
+	// MarkUsed kod daha önce kullanılmamışsa işaretler ve true döner
+	MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error)
+	SetSession(ctx context.Context, id, sessionID primitive.ObjectID) error
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+type OAuthClientService interface {
+	// Register istemciyi kaydeder; gizli istemcilerin secret'ı sadece burada, bir kez döner
+	Register(ctx context.Context, client *OAuthClient) (string, error)
+	// EnsureServiceClient ortam değişkenleriyle verilen servis istemcisini (örn. waste-service) hazırlar
+	EnsureServiceClient(ctx context.Context, id, secret string, scopes []string) error
+	List(ctx context.Context) ([]*OAuthClient, error)
+	Get(ctx context.Context, id string) (*OAuthClient, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type OIDCService interface {
+	Discovery() *OIDCDiscovery
+	// ValidateAuthorization isteği doğrular ve istenen scope'ları döner. İstemci nil dönerse
+	// (bilinmeyen istemci, kayıtsız redirect_uri) hata redirect_uri'ye gönderilmemelidir.
+	ValidateAuthorization(ctx context.Context, req AuthorizationRequest) (*OAuthClient, []string, error)
+	// IssueCode giriş yapan kullanıcı için yetkilendirme kodu üretir
+	IssueCode(ctx context.Context, req AuthorizationRequest, user *User, amr []string) (string, error)
+	Token(ctx context.Context, req TokenRequest, client ClientInfo) (*OIDCTokenResponse, error)
+	// Introspect token'ın durumunu döner; çağıran istemcinin token:introspect scope'u olmalıdır
+	Introspect(ctx context.Context, clientID, clientSecret, token string) (*TokenIntrospection, error)
+	// VerifyClientToken servis token'ını doğrular ve istemciyi döner; scope verilirse token'da olmalıdır
+	VerifyClientToken(ctx context.Context, token, scope string) (*OAuthClient, error)
+	// UserInfo access token'daki scope'lara göre kullanıcı claim'lerini döner
+	UserInfo(ctx context.Context, userID string, scopes []string) (map[string]interface{}, error)
+}
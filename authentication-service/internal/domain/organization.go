@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultOrganizationID organizasyon öncesi kayıtların taşındığı ve kayıtta organizasyon
+// seçilmezse kullanılan organizasyon
+const DefaultOrganizationID = "default"
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrOrganizationExists    = errors.New("organization already exists")
+	ErrOrganizationInactive  = errors.New("organization is not active")
+	ErrInvalidOrganizationID = errors.New("invalid organization id")
+)
+
+// Organization bir belediye (tenant). Kullanıcılar, atıklar ve toplama noktaları tek bir
+// organizasyona aittir; ID token'a "org" claim'i olarak yazılır ve waste servisi sorguları bununla sınırlar.
+type Organization struct {
+	ID        string    `bson:"_id" json:"id"` // Küçük harf slug, örn. "kadikoy"
+	Name      string    `bson:"name" json:"name"`
+	Active    bool      `bson:"active" json:"active"` // Pasif organizasyonun kullanıcıları token alamaz
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type OrganizationRepository interface {
+	List(ctx context.Context) ([]*Organization, error)
+	Get(ctx context.Context, id string) (*Organization, error)
+	Create(ctx context.Context, org *Organization) error
+	Update(ctx context.Context, org *Organization) error
+}
+
+type OrganizationService interface {
+	List(ctx context.Context) ([]*Organization, error)
+	Get(ctx context.Context, id string) (*Organization, error)
+	Create(ctx context.Context, org *Organization) error
+	Update(ctx context.Context, id string, org *Organization) error
+	// Resolve boş ID için varsayılan organizasyonu döner; organizasyon yoksa veya pasifse hata verir
+	Resolve(ctx context.Context, id string) (*Organization, error)
+}
+
+// UserOrganization eski kayıtlarda boş olabilen organizasyonu varsayılana tamamlar
+func UserOrganization(orgID string) string {
+	if orgID == "" {
+		return DefaultOrganizationID
+	}
+	return orgID
+}
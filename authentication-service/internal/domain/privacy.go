@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrWasteServiceUnavailable = errors.New("waste service unavailable")
+
+// Silme talebi durumları
+const (
+	ErasureStatusPending   = "pending"
+	ErasureStatusFailed    = "failed"
+	ErasureStatusCompleted = "completed"
+)
+
+// ErasureRequest KVKK/GDPR silme talebinin kaydı. Kullanıcı silindikten sonra da talebin
+// yerine getirildiğinin kanıtı olarak kalır; bu yüzden ID dışında kişisel veri tutmaz.
+type ErasureRequest struct {
+	ID                 primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID             string             `bson:"user_id" json:"user_id"`
+	OrgID              string             `bson:"org_id" json:"org_id"`
+	Status             string             `bson:"status" json:"status"`
+	WastesAnonymized   int64              `bson:"wastes_anonymized" json:"wastes_anonymized"`
+	RequestsAnonymized int64              `bson:"requests_anonymized" json:"requests_anonymized"`
+	ImagesDeleted      int                `bson:"images_deleted" json:"images_deleted"`
+	Error              string             `bson:"error,omitempty" json:"error,omitempty"`
+	RequestedAt        time.Time          `bson:"requested_at" json:"requested_at"`
+	CompletedAt        *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// WasteErasure waste servisinin silme cevabı
+type WasteErasure struct {
+	WastesAnonymized   int64 `json:"wastes_anonymized"`
+	RequestsAnonymized int64 `json:"requests_anonymized"`
+	ImagesDeleted      int   `json:"images_deleted"`
+}
+
+// WasteClient waste servisinin kişisel veri uçları; istekler kullanıcının token'ıyla değil,
+// servis istemcisinin client_credentials token'ıyla yapılır
+type WasteClient interface {
+	Export(ctx context.Context, userID string) ([]byte, error)
+	Erase(ctx context.Context, userID string) (*WasteErasure, error)
+}
+
+type ErasureRepository interface {
+	Create(ctx context.Context, request *ErasureRequest) error
+	Update(ctx context.Context, request *ErasureRequest) error
+	// List orgID boşsa tüm organizasyonların taleplerini döner
+	List(ctx context.Context, orgID string, limit int64) ([]*ErasureRequest, error)
+}
+
+type PrivacyService interface {
+	// Export hesap bilgileri ve waste servisindeki verilerden oluşan ZIP'i w'ye yazar
+	Export(ctx context.Context, userID string, w io.Writer) error
+	// Erase şifre onayıyla waste kayıtlarını anonimleştirir ve kullanıcıyı kalıcı siler
+	Erase(ctx context.Context, userID, password string) (*ErasureRequest, error)
+	ListErasures(ctx context.Context, orgID string, limit int64) ([]*ErasureRequest, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrUserNotDeleted restore/purge sadece soft-delete edilmiş kullanıcılara uygulanır
+var ErrUserNotDeleted = errors.New("user is not deleted")
+
+// Retention tetikleyicileri
+const (
+	RetentionTriggerSchedule = "schedule"
+	RetentionTriggerManual   = "manual"
+)
+
+// PurgedUser kalıcı silinen kullanıcının rapora yazılan özeti
+type PurgedUser struct {
+	ID        string    `bson:"id" json:"id"`
+	Email     string    `bson:"email" json:"email"`
+	DeletedAt time.Time `bson:"deleted_at" json:"deleted_at"`
+}
+
+// RetentionReport bir saklama süresi temizliğinin sonucu
+type RetentionReport struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Trigger   string             `bson:"trigger" json:"trigger"`
+	Cutoff    time.Time          `bson:"cutoff" json:"cutoff"` // Bu tarihten önce silinenler temizlendi
+	Purged    []PurgedUser       `bson:"purged" json:"purged"`
+	Count     int                `bson:"count" json:"count"`
+	Error     string             `bson:"error,omitempty" json:"error,omitempty"`
+	StartedAt time.Time          `bson:"started_at" json:"started_at"`
+	EndedAt   time.Time          `bson:"ended_at" json:"ended_at"`
+}
+
+type RetentionReportRepository interface {
+	Create(ctx context.Context, report *RetentionReport) error
+	List(ctx context.Context, limit int64) ([]*RetentionReport, error)
+}
+
+// RetentionService soft-delete edilmiş kullanıcıların geri alınması ve kalıcı silinmesi
+type RetentionService interface {
+	Restore(ctx context.Context, id string) error
+	// Purge soft-delete edilmiş kullanıcıyı oturumları ve token'larıyla birlikte kalıcı siler
+	Purge(ctx context.Context, id string) error
+	// Run saklama süresini aşan kullanıcıları siler ve raporu kaydeder
+	Run(ctx context.Context, trigger string) (*RetentionReport, error)
+	Reports(ctx context.Context, limit int64) ([]*RetentionReport, error)
+	// Start Run'ı arka planda periyodik çalıştırır
+	Start(ctx context.Context)
+}
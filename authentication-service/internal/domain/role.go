@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrRoleInUse         = errors.New("role is assigned to users")
+	ErrSystemRole        = errors.New("system roles cannot be deleted")
+	ErrInvalidRoleName   = errors.New("invalid role name")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrSuperAdminRole    = errors.New("only super admins can assign the super_admin role")
+)
+
+// Yetkiler "kaynak:işlem" biçimindedir. Waste servisindeki yetkiler de burada tanımlanır
+// çünkü rol → yetki eşlemesi token'a auth servisinde yazılır.
+const (
+	PermissionAll = "*"
+
+	PermUsersRead        = "users:read" // Kullanıcıları listeler ve başkalarının kaydını görür
+	PermUsersCreate      = "users:create"
+	PermUsersUpdate      = "users:update" // Başka kullanıcıların profil ve adreslerini düzenleyebilir
+	PermUsersDelete      = "users:delete"
+	PermUsersManageRoles = "users:manage_roles"
+	PermUsersUnlock      = "users:unlock"
+	PermUsersSessions    = "users:sessions"    // Başka kullanıcıların oturumlarını görüp kapatabilir
+	PermUsersAPIKeys     = "users:api_keys"    // Başka kullanıcılar (örn. kiosk hesapları) adına API anahtarı yönetebilir
+	PermUsersImpersonate = "users:impersonate" // Destek için kullanıcının yerine geçebilir; sadece kendi yetkileri kadar
+	PermRolesManage      = "roles:manage"
+	PermAuditRead        = "audit:read"
+
+	PermWastesRequest      = "wastes:request"
+	PermWastesUpdateStatus = "wastes:update_status"
+	PermWastesDelete       = "wastes:delete"
+	PermPointsManage       = "points:manage"
+)
+
+// Permissions rollere atanabilecek tüm yetkiler; yazım hatalarını yakalamak için kullanılır
+var Permissions = []string{
+	PermUsersRead, PermUsersCreate, PermUsersUpdate, PermUsersDelete, PermUsersManageRoles, PermUsersUnlock, PermUsersSessions, PermUsersAPIKeys, PermUsersImpersonate, PermRolesManage, PermAuditRead,
+	PermWastesRequest, PermWastesUpdateStatus, PermWastesDelete, PermPointsManage,
+}
+
+const (
+	RoleSuperAdmin           = "super_admin" // Tüm organizasyonlarda çalışır, organizasyonları ve rolleri yönetir
+	RoleAdmin                = "admin"       // Kendi organizasyonunda tüm yetkiler
+	RoleUser                 = "user"
+	RoleCollector            = "collector"
+	RoleMunicipalityOperator = "municipality_operator"
+	RoleRecyclerPartner      = "recycler_partner"
+)
+
+// Role bir rolün sahip olduğu yetkiler. Token'a rolün yanında yetkiler de yazılır,
+// bu yüzden yapılan değişiklikler kullanıcının bir sonraki login/refresh'inde geçerli olur.
+type Role struct {
+	Name        string    `bson:"_id" json:"name"`
+	Description string    `bson:"description" json:"description"`
+	Permissions []string  `bson:"permissions" json:"permissions"`
+	System      bool      `bson:"system" json:"system"` // Varsayılan roller silinemez
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultRoles uygulama ilk açıldığında eksikse oluşturulan roller
+var DefaultRoles = []Role{
+	{Name: RoleSuperAdmin, Description: "Tüm organizasyonlarda tüm yetkiler", Permissions: []string{PermissionAll}, System: true},
+	{Name: RoleAdmin, Description: "Tüm yetkiler", Permissions: []string{PermissionAll}, System: true},
+	{Name: RoleUser, Description: "Vatandaş", Permissions: []string{PermWastesRequest}, System: true},
+	{Name: RoleCollector, Description: "Atık toplayıcı", Permissions: []string{PermWastesRequest, PermWastesUpdateStatus}, System: true},
+	{Name: RoleMunicipalityOperator, Description: "Belediye operatörü", Permissions: []string{
+		PermWastesRequest, PermWastesUpdateStatus, PermWastesDelete, PermPointsManage, PermUsersRead, PermUsersUnlock,
+	}, System: true},
+	{Name: RoleRecyclerPartner, Description: "Geri dönüşüm ortağı", Permissions: []string{PermWastesUpdateStatus}, System: true},
+}
+
+// IsSuperAdmin organizasyon sınırı uygulanmayan rol mü. Yetki yerine role bakılır çünkü
+// organizasyon admin'leri de "*" yetkisine sahiptir.
+func IsSuperAdmin(role string) bool {
+	return role == RoleSuperAdmin
+}
+
+// HasPermission "*" ve "kaynak:*" joker karakterlerini de dikkate alır
+func HasPermission(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, p := range granted {
+		if p == PermissionAll || p == required || p == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+type RoleRepository interface {
+	List(ctx context.Context) ([]*Role, error)
+	Get(ctx context.Context, name string) (*Role, error)
+	Create(ctx context.Context, role *Role) error
+	Update(ctx context.Context, role *Role) error
+	Delete(ctx context.Context, name string) error
+	// EnsureDefaults eksik varsayılan rolleri ekler, admin tarafından değiştirilmiş olanlara dokunmaz
+	EnsureDefaults(ctx context.Context, roles []Role) error
+}
+
+type RoleService interface {
+	List(ctx context.Context) ([]*Role, error)
+	Get(ctx context.Context, name string) (*Role, error)
+	Create(ctx context.Context, role *Role) error
+	Update(ctx context.Context, name string, role *Role) error
+	Delete(ctx context.Context, name string) error
+	// Permissions token'a yazılacak yetkileri döner; tanımsız rol için boş liste
+	Permissions(ctx context.Context, roleName string) ([]string, error)
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// Session bir login ile başlayan refresh token ailesidir.
+// Access token'lar "sid" claim'i ile bu kayda bağlanır; oturum iptal edilince
+// aileye ait tüm token'lar geçersiz olur.
+type Session struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	AMR       []string           `bson:"amr,omitempty" json:"amr,omitempty"` // Login'de kullanılan doğrulama yöntemleri, refresh'te token'a taşınır
+	UserAgent string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"` // Son görülen IP (login veya refresh)
+	ClientID  string             `bson:"client_id,omitempty" json:"client_id,omitempty"`
+	Scopes    []string           `bson:"scopes,omitempty" json:"scopes,omitempty"`       // OIDC istemcisinin onaylanan scope'ları; kendi arayüzümüzde boş
+	Delegated bool               `bson:"delegated,omitempty" json:"delegated,omitempty"` // Üçüncü taraf istemci oturumu: token rol ve yetki taşımaz
+	// Impersonation oturumu: destek personeli kullanıcının yerine geçer, refresh token verilmez
+	ImpersonatorID    string     `bson:"impersonator_id,omitempty" json:"impersonator_id,omitempty"`
+	ImpersonatorEmail string     `bson:"impersonator_email,omitempty" json:"impersonator_email,omitempty"`
+	Reason            string     `bson:"reason,omitempty" json:"reason,omitempty"`
+	OrgID             string     `bson:"org_id,omitempty" json:"org_id,omitempty"` // Listelemeyi organizasyona göre kapsamak için
+	ExpiresAt         *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	CreatedAt         time.Time  `bson:"created_at" json:"created_at"`
+	LastSeenAt        time.Time  `bson:"last_seen_at" json:"last_seen_at"`
+	RevokedAt         *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	Current           bool       `bson:"-" json:"current"` // İsteği yapan token bu oturuma ait
+}
+
+// RevokedSession diğer servislerin iptal edilen oturumları takip ettiği akıştaki kayıt
+type RevokedSession struct {
+	SessionID string    `json:"session_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevokedSessionFeed iptal akışının cevabı; istemci bir sonraki sorguda Until'i since olarak gönderir
+type RevokedSessionFeed struct {
+	Sessions []RevokedSession `json:"sessions"`
+	Until    time.Time        `json:"until"`
+}
+
+// RefreshToken veritabanında sadece hash'i ile tutulur, her kullanımda yenisiyle değiştirilir
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SessionID primitive.ObjectID `bson:"session_id" json:"session_id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// TokenPair login ve refresh sonrası istemciye dönen token çifti
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // Access token ömrü (saniye)
+}
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *Session) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	// ListUserSessions kullanıcının açık oturumlarını son görülme zamanına göre döner
+	ListUserSessions(ctx context.Context, userID string) ([]*Session, error)
+	// ListImpersonations impersonation oturumlarını en yeniden eskiye doğru döner
+	ListImpersonations(ctx context.Context, filter ImpersonationFilter) ([]*Session, int64, error)
+	// ListRevokedSince since'ten sonra iptal edilen oturumları döner
+	ListRevokedSince(ctx context.Context, since time.Time) ([]*Session, error)
+	// TouchSession son görülme zamanını günceller; boş olmayan istemci bilgisi de yazılır
+	TouchSession(ctx context.Context, id primitive.ObjectID, client ClientInfo) error
+	RevokeSession(ctx context.Context, id primitive.ObjectID) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// RevokeClientSessions silinen OIDC istemcisine verilmiş tüm oturumları kapatır
+	RevokeClientSessions(ctx context.Context, clientID string) error
+	// DeleteUserSessions kullanıcının oturum ve refresh token kayıtlarını kalıcı siler
+	DeleteUserSessions(ctx context.Context, userID string) error
+	// RevokeOtherSessions kullanıcının keep dışındaki tüm oturumlarını kapatır
+	RevokeOtherSessions(ctx context.Context, userID string, keep primitive.ObjectID) error
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*RefreshToken, error)
+	// MarkRefreshTokenUsed token daha önce kullanılmamışsa işaretler ve true döner
+	MarkRefreshTokenUsed(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+type SessionService interface {
+	// List kullanıcının açık oturumlarını döner; currentSessionID olan Current ile işaretlenir
+	List(ctx context.Context, userID, currentSessionID string) ([]*Session, error)
+	// Revoke kullanıcının tek bir oturumunu kapatır; başkasının oturumu için ErrSessionNotFound
+	Revoke(ctx context.Context, userID, sessionID string) error
+	// RevokeAll kullanıcının tüm oturumlarını kapatır (kaybolan cihaz, ele geçirilen hesap)
+	RevokeAll(ctx context.Context, userID string) error
+	// RevokedSince access token ömrü içinde iptal edilen oturumları döner
+	RevokedSince(ctx context.Context, since time.Time) (*RevokedSessionFeed, error)
+}
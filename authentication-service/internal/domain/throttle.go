@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ClientInfo isteği yapan istemcinin ağ bilgileri
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginThrottleRecord bir hesap ("account:<email>") veya IP ("ip:<adres>") için başarısız deneme sayacı
+type LoginThrottleRecord struct {
+	Key           string     `bson:"_id" json:"key"`
+	Failures      int        `bson:"failures" json:"failures"`
+	LastFailureAt time.Time  `bson:"last_failure_at" json:"last_failure_at"`
+	LockedUntil   *time.Time `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// Kilit olay türleri
+const (
+	LockoutEventAccountLocked = "account_locked"
+	LockoutEventIPLocked      = "ip_locked"
+	LockoutEventUnlocked      = "unlocked"
+)
+
+// LockoutEvent hesap/IP kilitlenmesi ve admin tarafından kilit açılması kayıtları
+type LockoutEvent struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	Email       string             `bson:"email,omitempty" json:"email,omitempty"`
+	IP          string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	Failures    int                `bson:"failures" json:"failures"`
+	LockedUntil *time.Time         `bson:"locked_until,omitempty" json:"locked_until,omitempty"`
+	ActorID     string             `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // Kilidi açan admin
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// LockoutError çok fazla başarısız denemeden sonra döner; RetryAfter kadar beklenmelidir
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", int(e.RetryAfter.Seconds())+1)
+}
+
+type LoginThrottleRepository interface {
+	Get(ctx context.Context, key string) (*LoginThrottleRecord, error)
+	// RegisterFailure sayacı atomik olarak artırır; son hata window'dan eskiyse sayaç 1'den başlar
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (*LoginThrottleRecord, error)
+	SetLockedUntil(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	RecordEvent(ctx context.Context, event *LockoutEvent) error
+	// DeleteEvents e-postaya ait kilit olaylarını siler (kalıcı silinen kullanıcılar için)
+	DeleteEvents(ctx context.Context, email string) error
+}
+
+type LoginThrottle interface {
+	// Check hesap veya IP kilitliyse *LockoutError döner
+	Check(ctx context.Context, email, ip string) error
+	RegisterFailure(ctx context.Context, email, ip string) error
+	RegisterSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email, actorID string) error
+	// Forget hesabın sayaç ve kilit geçmişini siler; IP sayaçları kişiye bağlı olmadığı için kalır
+	Forget(ctx context.Context, email string) error
+}
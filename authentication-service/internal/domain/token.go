@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tek kullanımlık token amaçları
+const (
+	TokenPurposePasswordReset = "password_reset"
+)
+
+var (
+	ErrInvalidOneTimeToken = errors.New("invalid or expired token")
+	ErrWeakPassword        = errors.New("password must be at least 8 characters")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+)
+
+// OneTimeToken e-posta ile gönderilen, hash'lenmiş olarak saklanan tek kullanımlık token
+type OneTimeToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	Purpose   string             `bson:"purpose" json:"purpose"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type OneTimeTokenRepository interface {
+	Create(ctx context.Context, token *OneTimeToken) error
+	GetByHash(ctx context.Context, purpose, hash string) (*OneTimeToken, error)
+	// MarkUsed token daha önce kullanılmamışsa işaretler ve true döner
+	MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// DeleteUserTokens kullanıcının verilen amaçla açık kalan token'larını siler
+	DeleteUserTokens(ctx context.Context, userID, purpose string) error
+	// DeleteByUser kullanıcının kullanılmış olanlar dahil tüm token'larını kalıcı siler
+	DeleteByUser(ctx context.Context, userID string) error
+}
+
+type VerificationService interface {
+	// SendVerification kullanıcıya imzalı doğrulama linki gönderir
+	SendVerification(ctx context.Context, user *User) error
+	Verify(ctx context.Context, token string) error
+	Resend(ctx context.Context, email string) error
+}
+
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// ChangePassword mevcut şifreyi doğrulayıp değiştirir; currentSessionID dışındaki oturumlar kapatılır
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword, currentSessionID string) error
+}
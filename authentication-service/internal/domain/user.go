@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -9,45 +10,144 @@ import (
 
 // Address Value Object
 type Address struct {
-	Title       string `bson:"title" json:"title" example:"Ev"`
-	City        string `bson:"city" json:"city" example:"Istanbul"`
-	District    string `bson:"district" json:"district" example:"Kadikoy"`
-	FullAddress string `bson:"full_address" json:"full_address" example:"Caferaga mah. Moda cad. No:1"`
+	ID          primitive.ObjectID `bson:"id,omitempty" json:"id"`
+	Title       string             `bson:"title" json:"title" example:"Ev"`
+	City        string             `bson:"city" json:"city" example:"Istanbul"`
+	District    string             `bson:"district" json:"district" example:"Kadikoy"`
+	FullAddress string             `bson:"full_address" json:"full_address" example:"Caferaga mah. Moda cad. No:1"`
+	Latitude    float64            `bson:"latitude,omitempty" json:"latitude,omitempty" example:"40.99"`    // Adres geocode edilemezse boş kalır
+	Longitude   float64            `bson:"longitude,omitempty" json:"longitude,omitempty" example:"29.029"` // Adres geocode edilemezse boş kalır
+	IsDefault   bool               `bson:"is_default" json:"is_default"`                                    // Atık toplama için varsayılan adres
 }
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email     string             `bson:"email" json:"email"`
-	FirstName string             `bson:"first_name" json:"first_name"`
-	LastName  string             `bson:"last_name" json:"last_name"`
-	Password  string             `bson:"password" json:"-"`
-	Addresses []Address          `bson:"addresses" json:"addresses"`
-	Role      string             `bson:"role" json:"role"` // "admin" veya "user"
-	Active    bool               `bson:"active" json:"active"`
-	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Soft delete
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email               string             `bson:"email" json:"email"`
+	FirstName           string             `bson:"first_name" json:"first_name"`
+	LastName            string             `bson:"last_name" json:"last_name"`
+	Password            string             `bson:"password" json:"-"`
+	Addresses           []Address          `bson:"addresses" json:"addresses"`
+	Role                string             `bson:"role" json:"role"`     // "admin", "collector" veya "user"
+	OrgID               string             `bson:"org_id" json:"org_id"` // Kullanıcının belediyesi (organizasyon)
+	Active              bool               `bson:"active" json:"active"`
+	VerificationPending bool               `bson:"verification_pending" json:"verification_pending"` // E-posta doğrulanana kadar true (alanı olmayan eski kayıtlar doğrulanmış sayılır)
+	EmailVerifiedAt     *time.Time         `bson:"email_verified_at,omitempty" json:"email_verified_at,omitempty"`
+	MFAEnabled          bool               `bson:"mfa_enabled" json:"mfa_enabled"`
+	MFASecret           string             `bson:"mfa_secret" json:"-"`
+	MFAPendingSecret    string             `bson:"mfa_pending_secret" json:"-"`                      // Kurulumu tamamlanmamış secret
+	MFARecoveryCodes    []string           `bson:"mfa_recovery_codes" json:"-"`                      // SHA-256 hash'leri
+	MFALastStep         int64              `bson:"mfa_last_step" json:"-"`                           // Replay koruması için son kullanılan TOTP adımı
+	DeletedAt           *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Soft delete
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// UserUpdate yönetici güncellemesi; gönderilmeyen (nil) alanlar değişmez
+type UserUpdate struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email"` // Değişirse yeni adres doğrulanana kadar hesap doğrulanmamış sayılır
+	Active    *bool   `json:"active"`
+	Role      *string `json:"role"` // Boş rol mevcut rolü korur
+	// Şifre bu uçtan değişmez; kullanıcı /users/me/password veya sıfırlama akışını kullanır
+}
+
+// UserFilter kullanıcı listeleme parametreleri. Boş alanlar filtre uygulanmaz demektir.
+type UserFilter struct {
+	OrgID       string // Boşsa tüm organizasyonlar (sadece süper admin)
+	Role        string
+	Active      *bool
+	Deleted     *bool // nil: silinmemişler, true: sadece silinmişler, false: sadece silinmemişler
+	City        string
+	District    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Search      string // Ad, soyad ve e-postada geçen metin
+	Sort        string // created_at, updated_at, email, first_name, last_name
+	Desc        bool
+	Page        int
+	Limit       int
+}
+
+// UserPage sayfalanmış liste cevabı
+type UserPage struct {
+	Items []*User `json:"items"`
+	Total int64   `json:"total"`
+	Page  int     `json:"page"`
+	Limit int     `json:"limit"`
+}
+
+var (
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	ErrEmailInUse       = errors.New("email already in use")
+	ErrEmailRequired    = errors.New("email is required")
+	ErrUserNotFound     = errors.New("user not found")
+)
+
 type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByID(ctx context.Context, id string) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	// List filtreye uyan kullanıcıların istenen sayfasını ve toplam sayısını döner
+	List(ctx context.Context, filter UserFilter) ([]*User, int64, error)
+	CountByRole(ctx context.Context, role string) (int64, error)
+	// Create hesabı açar; admin'ler kullanıcıyı doğrudan değil InvitationService ile davet ederek ekler
 	Create(ctx context.Context, user *User) error
-	Update(ctx context.Context, user *User) error
+	// Update sadece verilen bson alanlarını (ve updated_at'i) yazar; eşzamanlı adres gibi
+	// başka uçlardan yapılan değişiklikler ezilmez
+	Update(ctx context.Context, user *User, fields ...string) error
 	Delete(ctx context.Context, id string) error
-	SeedUsers(ctx context.Context) error
+	// Restore soft delete'i geri alır, kullanıcı silinmiş değilse false döner
+	Restore(ctx context.Context, id string) (bool, error)
+	// Purge sadece soft-delete edilmiş kullanıcıyı kalıcı siler
+	Purge(ctx context.Context, id primitive.ObjectID) (bool, error)
+	// ListDeletedBefore cutoff'tan önce soft-delete edilmiş kullanıcıları döner
+	ListDeletedBefore(ctx context.Context, cutoff time.Time, limit int64) ([]*User, error)
+	// ConsumeMFAStep son kullanılan TOTP adımından büyükse adımı kaydeder; aynı kod iki kez kullanılamaz
+	ConsumeMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error)
+	// ConsumeRecoveryCode kurtarma kodunu listeden atomik olarak siler, kod yoksa false döner
+	ConsumeRecoveryCode(ctx context.Context, id primitive.ObjectID, codeHash string) (bool, error)
+	// Adres defteri: tek bir adres üzerinde atomik işlemler, false dönerse adres bulunamadı
+	AddAddress(ctx context.Context, userID primitive.ObjectID, address Address) error
+	UpdateAddress(ctx context.Context, userID primitive.ObjectID, address Address) (bool, error)
+	// RemoveAddress kullanıcının tek adresini silmez; varsayılan adres silinirse ilk adres varsayılan olur
+	RemoveAddress(ctx context.Context, userID, addressID primitive.ObjectID) (bool, error)
+	SetDefaultAddress(ctx context.Context, userID, addressID primitive.ObjectID) (bool, error)
+	// InsertIfMissing aynı e-postaya sahip kullanıcı yoksa ekler (fixture'lar için)
+	InsertIfMissing(ctx context.Context, user *User) (bool, error)
 }
 
 type AuthService interface {
-	Login(ctx context.Context, email, password string) (string, *User, error)
+	Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error)
+	// LoginMFA şifre adımından dönen mfa token'ını TOTP veya kurtarma kodu ile token'lara çevirir
+	LoginMFA(ctx context.Context, mfaToken, code, recoveryCode string, client ClientInfo) (*LoginResult, error)
+	// BeginMFAEnrollment MFA zorunlu rolde kurulumu yapılmamış kullanıcı için login sırasında secret üretir
+	BeginMFAEnrollment(ctx context.Context, mfaToken string) (*MFAEnrollment, error)
+	// Authenticate şifreyi oturum açmadan doğrular (OIDC yetkilendirme formu); MFA gerekiyorsa MFAToken döner
+	Authenticate(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error)
+	// AuthenticateMFA Authenticate'ten dönen mfa token'ını oturum açmadan doğrular
+	AuthenticateMFA(ctx context.Context, mfaToken, code, recoveryCode string, client ClientInfo) (*LoginResult, error)
+	// StartClientSession OIDC istemcisi adına oturum açar; session'da UserID, AMR, ClientID, Scopes ve Delegated dolu gelir
+	StartClientSession(ctx context.Context, session *Session, client ClientInfo) (*LoginResult, error)
+	// StartImpersonation refresh token'ı olmayan, session.ExpiresAt'e kadar geçerli bir access token verir;
+	// session'da UserID, ImpersonatorID, ImpersonatorEmail, Reason, OrgID ve ExpiresAt dolu gelir
+	StartImpersonation(ctx context.Context, session *Session, client ClientInfo) (*TokenPair, error)
 	Register(ctx context.Context, user *User) error
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
 }
 
 type UserService interface {
 	Get(ctx context.Context, id string) (*User, error)
-	GetAll(ctx context.Context) ([]*User, error)
+	List(ctx context.Context, filter UserFilter) (*UserPage, error)
 	Create(ctx context.Context, user *User) error
-	Update(ctx context.Context, id string, user *User) error
+	// Update yönetici güncellemesidir; sadece gönderilen alanlar yazılır, rol doğrulanıp değiştirilir
+	Update(ctx context.Context, id string, update UserUpdate) error
+	// UpdateProfile kullanıcının kendi profilini günceller; rol, aktiflik, e-posta ve şifre yok sayılır
+	UpdateProfile(ctx context.Context, id string, profile *User) error
 	Delete(ctx context.Context, id string) error
+	// ChangeRole rolün tanımlı olduğunu doğrulayıp kullanıcıya atar
+	ChangeRole(ctx context.Context, id, role string) error
+	// Unlock başarısız denemeler nedeniyle kilitlenen hesabı açar
+	Unlock(ctx context.Context, id, actorID string) error
 }
@@ -0,0 +1,113 @@
+// Package fixtures geliştirme ve test ortamları için demo kullanıcıları yükler.
+// Varsayılan olarak kapalıdır ve production ortamında hiçbir koşulda çalışmaz.
+// Kayıtlar e-posta adresine göre sadece eksikse eklenir; var olan kullanıcılara dokunulmaz.
+package fixtures
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrProductionEnvironment fixture'lar production ortamında yüklenmeye çalışıldığında döner
+var ErrProductionEnvironment = errors.New("fixtures are disabled in production")
+
+type UserFixture struct {
+	Email     string           `json:"email"`
+	Password  string           `json:"password"`
+	FirstName string           `json:"first_name"`
+	LastName  string           `json:"last_name"`
+	Role      string           `json:"role"`
+	OrgID     string           `json:"org_id"` // Boşsa varsayılan organizasyon
+	Addresses []domain.Address `json:"addresses"`
+}
+
+type File struct {
+	Users []UserFixture `json:"users"`
+}
+
+// Load .yaml/.yml veya .json uzantılı fixture dosyasını okur
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := decode(path, data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Apply fixture kullanıcılarını ekler. appEnv "production" ise hiçbir şey yapmadan hata döner.
+func Apply(ctx context.Context, appEnv string, repo domain.UserRepository, file *File) error {
+	if strings.EqualFold(appEnv, "production") {
+		return ErrProductionEnvironment
+	}
+
+	for _, f := range file.Users {
+		if f.Email == "" || f.Password == "" {
+			return fmt.Errorf("fixture user requires email and password")
+		}
+
+		hashed, err := bcrypt.GenerateFromPassword([]byte(f.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		role := f.Role
+		if role == "" {
+			role = domain.RoleUser
+		}
+
+		user := &domain.User{
+			Email:     f.Email,
+			Password:  string(hashed),
+			FirstName: f.FirstName,
+			LastName:  f.LastName,
+			Role:      role,
+			OrgID:     domain.UserOrganization(f.OrgID),
+			Addresses: domain.PrepareAddresses(f.Addresses),
+			Active:    true,
+		}
+		inserted, err := repo.InsertIfMissing(ctx, user)
+		if err != nil {
+			return fmt.Errorf("fixture user %s: %w", f.Email, err)
+		}
+		if inserted {
+			log.Printf("Fixture user created: %s (%s)", f.Email, role)
+		}
+	}
+	return nil
+}
+
+// decode YAML'ı önce genel bir yapıya açıp JSON'a çevirir; böylece struct'larda
+// sadece json tag'leri yeterli olur ve iki format aynı alan adlarını kullanır
+func decode(path string, data []byte, v interface{}) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.Unmarshal(data, v)
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		converted, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(converted, v)
+	default:
+		return fmt.Errorf("unsupported fixture format %q", filepath.Ext(path))
+	}
+}
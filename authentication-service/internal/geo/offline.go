@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"authentication-service/internal/domain"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// İl/ilçe merkez koordinatları. Sokak seviyesinde değil; en yakın nokta aramasına başlangıç için yeterli.
+//
+//go:embed data/tr_centroids.csv
+var centroidsCSV []byte
+
+type point struct {
+	lat, lng float64
+}
+
+type offlineGeocoder struct {
+	provinces map[string]point
+	districts map[string]point // anahtar: "il|ilçe"
+}
+
+// NewOfflineGeocoder gömülü il/ilçe veri setini yükler; dış servise istek atmaz.
+func NewOfflineGeocoder() (domain.Geocoder, error) {
+	reader := csv.NewReader(bytes.NewReader(centroidsCSV))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("geo dataset: %w", err)
+	}
+
+	g := &offlineGeocoder{
+		provinces: make(map[string]point),
+		districts: make(map[string]point),
+	}
+	for _, r := range records {
+		lat, err := strconv.ParseFloat(r[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geo dataset: %s/%s: %w", r[0], r[1], err)
+		}
+		lng, err := strconv.ParseFloat(r[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("geo dataset: %s/%s: %w", r[0], r[1], err)
+		}
+
+		province := normalize(r[0])
+		if r[1] == "" {
+			g.provinces[province] = point{lat, lng}
+		} else {
+			g.districts[province+"|"+normalize(r[1])] = point{lat, lng}
+		}
+	}
+	return g, nil
+}
+
+// Geocode önce ilçe merkezini, bulamazsa il merkezini döner.
+func (g *offlineGeocoder) Geocode(_ context.Context, city, district string) (float64, float64, error) {
+	province := normalize(city)
+	if district != "" {
+		if p, ok := g.districts[province+"|"+normalize(district)]; ok {
+			return p.lat, p.lng, nil
+		}
+	}
+	if p, ok := g.provinces[province]; ok {
+		return p.lat, p.lng, nil
+	}
+	return 0, 0, domain.ErrLocationUnknown
+}
+
+var turkishFold = strings.NewReplacer(
+	"İ", "i", "I", "i", "ı", "i",
+	"Ç", "c", "ç", "c",
+	"Ğ", "g", "ğ", "g",
+	"Ö", "o", "ö", "o",
+	"Ş", "s", "ş", "s",
+	"Ü", "u", "ü", "u",
+	"Â", "a", "â", "a",
+)
+
+// normalize Türkçe karakterleri ASCII'ye indirger ve küçük harfe çevirir;
+// "İSTANBUL", "Istanbul" ve "istanbul" aynı anahtara düşer.
+func normalize(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(turkishFold.Replace(name)), " "))
+}
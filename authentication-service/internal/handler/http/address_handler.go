@@ -0,0 +1,155 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AddressHandler struct {
+	service domain.AddressService
+}
+
+func NewAddressHandler(service domain.AddressService) *AddressHandler {
+	return &AddressHandler{
+		service: service,
+	}
+}
+
+// List godoc
+// @Summary Adresleri Listele
+// @Description Kullanıcının adres defterini döner.
+// @Tags Addresses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} domain.Address
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/addresses [get]
+func (h *AddressHandler) List(c echo.Context) error {
+	addresses, err := h.service.List(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusOK, addresses)
+}
+
+// Default godoc
+// @Summary Varsayılan Adres
+// @Description Kullanıcının varsayılan (atık toplama) adresini döner. Waste servisi toplama taleplerinde bu adresi kullanır.
+// @Tags Addresses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} domain.Address
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/addresses/default [get]
+func (h *AddressHandler) Default(c echo.Context) error {
+	address, err := h.service.Default(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusOK, address)
+}
+
+// Create godoc
+// @Summary Adres Ekle
+// @Description Adres defterine yeni adres ekler. is_default=true ise varsayılan adres olur.
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body domain.Address true "Adres"
+// @Success 201 {object} domain.Address
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/addresses [post]
+func (h *AddressHandler) Create(c echo.Context) error {
+	var address domain.Address
+	if err := c.Bind(&address); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	if err := h.service.Add(c.Request().Context(), c.Param("id"), &address); err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusCreated, address)
+}
+
+// Update godoc
+// @Summary Adres Güncelle
+// @Description Tek bir adresi günceller; diğer adreslere dokunulmaz.
+// @Tags Addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param addressId path string true "Address ID"
+// @Param request body domain.Address true "Adres"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/addresses/{addressId} [put]
+func (h *AddressHandler) Update(c echo.Context) error {
+	var address domain.Address
+	if err := c.Bind(&address); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	if err := h.service.Update(c.Request().Context(), c.Param("id"), c.Param("addressId"), &address); err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "address updated"})
+}
+
+// Delete godoc
+// @Summary Adres Sil
+// @Description Adresi siler. Kullanıcının son adresi silinemez; varsayılan adres silinirse ilk adres varsayılan olur.
+// @Tags Addresses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param addressId path string true "Address ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/addresses/{addressId} [delete]
+func (h *AddressHandler) Delete(c echo.Context) error {
+	if err := h.service.Delete(c.Request().Context(), c.Param("id"), c.Param("addressId")); err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "address deleted"})
+}
+
+// SetDefault godoc
+// @Summary Varsayılan Adresi Değiştir
+// @Tags Addresses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param addressId path string true "Address ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/addresses/{addressId}/default [put]
+func (h *AddressHandler) SetDefault(c echo.Context) error {
+	if err := h.service.SetDefault(c.Request().Context(), c.Param("id"), c.Param("addressId")); err != nil {
+		return addressError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "default address updated"})
+}
+
+func addressError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrAddressNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrLastAddress):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidAddress):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
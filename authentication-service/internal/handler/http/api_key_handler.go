@@ -0,0 +1,163 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	service domain.APIKeyService
+}
+
+func NewAPIKeyHandler(service domain.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`            // Örn. "Meydan kiosku"
+	Scopes        []string `json:"scopes"`          // Anahtar sahibinin rolündeki yetkilerden seçilir, "*" kabul edilmez
+	ExpiresInDays int      `json:"expires_in_days"` // Boşsa varsayılan süre (90 gün)
+}
+
+// CreateAPIKeyResponse key sadece bu cevapta döner, sonradan görüntülenemez
+type CreateAPIKeyResponse struct {
+	*domain.APIKey
+	Key string `json:"key"`
+}
+
+// ListMine godoc
+// @Summary API Anahtarlarım
+// @Description İptal edilmemiş API anahtarlarını (ad, prefix, scope'lar, son kullanım ve bitiş zamanı) listeler.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.APIKey
+// @Router /users/me/api-keys [get]
+func (h *APIKeyHandler) ListMine(c echo.Context) error {
+	return h.list(c, claimString(c, "user_id"))
+}
+
+// CreateMine godoc
+// @Summary API Anahtarı Oluştur
+// @Description Kiosk ve entegrasyonlar için uzun ömürlü anahtar oluşturur. Anahtar X-API-Key başlığıyla
+// her iki serviste de kullanılabilir ve sadece bu cevapta gösterilir. API anahtarıyla yeni anahtar oluşturulamaz.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "Anahtar"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Router /users/me/api-keys [post]
+func (h *APIKeyHandler) CreateMine(c echo.Context) error {
+	return h.create(c, claimString(c, "user_id"))
+}
+
+// RevokeMine godoc
+// @Summary API Anahtarını İptal Et
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeMine(c echo.Context) error {
+	return h.revoke(c, claimString(c, "user_id"), c.Param("id"))
+}
+
+// ListUser godoc
+// @Summary Kullanıcının API Anahtarları
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} domain.APIKey
+// @Router /admin/users/{id}/api-keys [get]
+func (h *APIKeyHandler) ListUser(c echo.Context) error {
+	return h.list(c, c.Param("id"))
+}
+
+// CreateUser godoc
+// @Summary Kullanıcı Adına API Anahtarı Oluştur
+// @Description Kiosk gibi ortak hesaplar için anahtar oluşturur. Scope'lar hedef kullanıcının rolünden seçilir.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body CreateAPIKeyRequest true "Anahtar"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Router /admin/users/{id}/api-keys [post]
+func (h *APIKeyHandler) CreateUser(c echo.Context) error {
+	return h.create(c, c.Param("id"))
+}
+
+// RevokeUser godoc
+// @Summary Kullanıcının API Anahtarını İptal Et
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/api-keys/{keyId} [delete]
+func (h *APIKeyHandler) RevokeUser(c echo.Context) error {
+	return h.revoke(c, c.Param("id"), c.Param("keyId"))
+}
+
+func (h *APIKeyHandler) list(c echo.Context, userID string) error {
+	keys, err := h.service.List(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) create(c echo.Context, userID string) error {
+	var req CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	key := &domain.APIKey{
+		UserID:    userID,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		CreatedBy: claimString(c, "user_id"),
+	}
+	secret, err := h.service.Create(c.Request().Context(), key, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusCreated, CreateAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+func (h *APIKeyHandler) revoke(c echo.Context, userID, id string) error {
+	if err := h.service.Revoke(c.Request().Context(), userID, id); err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "api key revoked"})
+}
+
+func apiKeyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNotFound), errors.Is(err, domain.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrAPIKeyLimit):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidAPIKeyName), errors.Is(err, domain.ErrInvalidAPIKeyScopes),
+		errors.Is(err, domain.ErrAPIKeyScope), errors.Is(err, domain.ErrInvalidAPIKeyExpiry),
+		errors.Is(err, domain.ErrUnknownPermission):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
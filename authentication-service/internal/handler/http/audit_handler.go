@@ -0,0 +1,120 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	service domain.AuditService
+}
+
+func NewAuditHandler(service domain.AuditService) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+	}
+}
+
+// List godoc
+// @Summary Denetim Kayıtları
+// @Description Her iki servisin yönetim ve güvenlik olaylarını en yeniden eskiye listeler.
+// Organizasyon admin'leri sadece kendi organizasyonlarının kayıtlarını görür.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param service query string false "authentication-service veya waste-service"
+// @Param action query string false "Olay (ör. user.role_change); user.* gibi önek kabul edilir"
+// @Param actor_id query string false "İşlemi yapan kullanıcı"
+// @Param impersonator_id query string false "Kullanıcı yerine geçerek işlem yapan destek personeli"
+// @Param target_type query string false "Hedef tipi (user, role, waste, point)"
+// @Param target_id query string false "Hedef ID"
+// @Param from query string false "Başlangıç (YYYY-MM-DD veya RFC3339)"
+// @Param to query string false "Bitiş (YYYY-MM-DD veya RFC3339)"
+// @Param page query int false "Sayfa (1'den başlar)"
+// @Param limit query int false "Sayfa boyutu (en fazla 500)"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {object} domain.AuditPage
+// @Failure 400 {object} map[string]string
+// @Router /admin/audit [get]
+func (h *AuditHandler) List(c echo.Context) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	page, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// Export godoc
+// @Summary Denetim Kayıtlarını Dışa Aktar
+// @Description Filtreye uyan tüm kayıtları eskiden yeniye CSV veya JSON Lines olarak indirir. Filtreler /admin/audit ile aynıdır.
+// @Tags Admin
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "csv (varsayılan) veya jsonl"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Router /admin/audit/export [get]
+func (h *AuditHandler) Export(c echo.Context) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	contentType := map[string]string{"csv": "text/csv; charset=utf-8", "jsonl": "application/x-ndjson"}[format]
+	if contentType == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": domain.ErrInvalidAuditFormat.Error()})
+	}
+
+	// Kayıtlar cursor'dan okunurken doğrudan cevaba yazılır; başlıklar yazıldıktan sonra hata sadece loglanabilir
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="audit-%s.%s"`, time.Now().UTC().Format("20060102-150405"), format))
+	res.WriteHeader(http.StatusOK)
+
+	if err := h.service.Export(c.Request().Context(), filter, format, res); err != nil && !errors.Is(err, domain.ErrInvalidAuditFormat) {
+		c.Logger().Error("audit export: ", err)
+	}
+	return nil
+}
+
+func parseAuditFilter(c echo.Context) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		Service:        c.QueryParam("service"),
+		Action:         c.QueryParam("action"),
+		ActorID:        c.QueryParam("actor_id"),
+		ImpersonatorID: c.QueryParam("impersonator_id"),
+		TargetType:     c.QueryParam("target_type"),
+		TargetID:       c.QueryParam("target_id"),
+		OrgID:          scopedOrganization(c),
+	}
+
+	var err error
+	if filter.Page, err = queryInt(c, "page"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = queryTime(c, "from", false); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryTime(c, "to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
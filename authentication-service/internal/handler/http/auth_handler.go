@@ -2,10 +2,9 @@ package http
 
 import (
 	"authentication-service/internal/domain"
+	"errors"
 	"net/http"
-
-	cfg "authentication-service/internal/config"
-	customMiddleware "authentication-service/internal/handler/middleware"
+	"strconv"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
@@ -13,13 +12,15 @@ import (
 
 type AuthHandler struct {
 	service domain.AuthService
-	config  *cfg.Config
+	keys    domain.KeyService
+	jwtAuth echo.MiddlewareFunc
 }
 
-func NewAuthHandler(service domain.AuthService) *AuthHandler {
+func NewAuthHandler(service domain.AuthService, keys domain.KeyService, jwtAuth echo.MiddlewareFunc) *AuthHandler {
 	return &AuthHandler{
 		service: service,
-		config:  cfg.LoadConfig(),
+		keys:    keys,
+		jwtAuth: jwtAuth,
 	}
 }
 
@@ -34,32 +35,75 @@ type RegisterRequest struct {
 	Password  string           `json:"password"`
 	FirstName string           `json:"first_name"`
 	LastName  string           `json:"last_name"`
-	Addresses []domain.Address `json:"addresses"` // Zorunlu alan
+	Addresses []domain.Address `json:"addresses"`    // Zorunlu alan
+	OrgID     string           `json:"organization"` // Belediye; boşsa varsayılan organizasyon
 }
 
 type LoginResponse struct {
-	Message string       `json:"message"`
-	Token   string       `json:"token"`
-	User    *domain.User `json:"user"`
+	Message      string       `json:"message"`
+	Token        string       `json:"token"` // Access token (eski istemcilerle uyumluluk için "token" adıyla)
+	RefreshToken string       `json:"refresh_token"`
+	ExpiresIn    int64        `json:"expires_in"`
+	User         *domain.User `json:"user"`
+	// Login sırasında MFA kurulumu tamamlandıysa kurtarma kodları yalnızca bu cevapta gösterilir
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// MFAChallengeResponse şifre doğru ama ikinci adım gerekiyorsa döner
+type MFAChallengeResponse struct {
+	Message               string `json:"message"`
+	MFARequired           bool   `json:"mfa_required"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required"` // true ise önce /auth/login/mfa/enroll ile secret alınmalı
+	MFAToken              string `json:"mfa_token"`
+}
+
+type LoginMFARequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"` // Authenticator kaybolduysa kod yerine kullanılabilir
+}
+
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
 }
 
 func (h *AuthHandler) RegisterRoutes(e *echo.Echo) {
 	e.POST("/auth/login", h.Login)
+	e.POST("/auth/login/mfa", h.LoginMFA)
+	e.POST("/auth/login/mfa/enroll", h.LoginMFAEnroll)
 	e.POST("/auth/register", h.Register)
+	e.POST("/auth/refresh", h.Refresh)
+	e.POST("/auth/logout", h.Logout)
 	e.GET("/health", h.HealthCheck)
-	e.GET("/auth/validate", h.ValidateToken, customMiddleware.JWTMiddleware(h.config.JWTSecret))
+	e.GET("/auth/validate", h.ValidateToken, h.jwtAuth)
+	e.GET("/.well-known/jwks.json", h.JWKS)
 }
 
 // Login godoc
 // @Summary Kullanıcı Girişi
-// @Description Email ve şifre ile giriş yapar, JWT token döner.
+// @Description Email ve şifre ile giriş yapar, kısa ömürlü access token ve refresh token döner.
+// Hesapta iki adımlı doğrulama açıksa (veya rol için zorunluysa) token yerine mfa_token döner; /auth/login/mfa ile tamamlanır.
+// Art arda başarısız denemelerde hesap ve IP bazında artan bekleme süresi uygulanır, limit aşılınca geçici olarak kilitlenir.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Giriş Bilgileri"
 // @Success 200 {object} LoginResponse
+// @Success 202 {object} MFAChallengeResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req LoginRequest
@@ -67,27 +111,181 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request"})
 	}
 
-	token, user, err := h.service.Login(c.Request().Context(), req.Email, req.Password)
+	client := domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	result, err := h.service.Login(c.Request().Context(), req.Email, req.Password, client)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return loginError(c, err)
+	}
+
+	if result.MFARequired {
+		return c.JSON(http.StatusAccepted, MFAChallengeResponse{
+			Message:               "mfa verification required",
+			MFARequired:           true,
+			MFAEnrollmentRequired: result.MFAEnrollmentRequired,
+			MFAToken:              result.MFAToken,
+		})
 	}
+	return c.JSON(http.StatusOK, newLoginResponse(result))
+}
+
+// LoginMFA godoc
+// @Summary İki Adımlı Giriş
+// @Description /auth/login'den dönen mfa_token ile authenticator kodunu (veya kurtarma kodunu) doğrular ve token'ları döner.
+// Kurulum gerekiyorsa gönderilen kod yeni secret'ı aktifleştirir ve kurtarma kodları cevapta bir kez döner.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginMFARequest true "MFA Bilgileri"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /auth/login/mfa [post]
+func (h *AuthHandler) LoginMFA(c echo.Context) error {
+	var req LoginMFARequest
+	if err := c.Bind(&req); err != nil || req.MFAToken == "" || (req.Code == "" && req.RecoveryCode == "") {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "mfa_token and code or recovery_code are required"})
+	}
+
+	client := domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	result, err := h.service.LoginMFA(c.Request().Context(), req.MFAToken, req.Code, req.RecoveryCode, client)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFANotEnrolled) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return loginError(c, err)
+	}
+	return c.JSON(http.StatusOK, newLoginResponse(result))
+}
+
+// LoginMFAEnroll godoc
+// @Summary Login Sırasında MFA Kurulumu
+// @Description MFA zorunlu olan ama henüz kurulum yapmamış kullanıcı için secret ve otpauth URI üretir.
+// Authenticator'a eklendikten sonra üretilen kod /auth/login/mfa'ya gönderilir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MFAEnrollRequest true "MFA Token"
+// @Success 200 {object} domain.MFAEnrollment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/login/mfa/enroll [post]
+func (h *AuthHandler) LoginMFAEnroll(c echo.Context) error {
+	var req MFAEnrollRequest
+	if err := c.Bind(&req); err != nil || req.MFAToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "mfa_token is required"})
+	}
+
+	enrollment, err := h.service.BeginMFAEnrollment(c.Request().Context(), req.MFAToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMFAToken) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, enrollment)
+}
 
-	return c.JSON(http.StatusOK, LoginResponse{
-		Message: "login successful",
-		Token:   token,
-		User:    user,
+func newLoginResponse(result *domain.LoginResult) LoginResponse {
+	return LoginResponse{
+		Message:       "login successful",
+		Token:         result.Tokens.AccessToken,
+		RefreshToken:  result.Tokens.RefreshToken,
+		ExpiresIn:     result.Tokens.ExpiresIn,
+		User:          result.User,
+		RecoveryCodes: result.RecoveryCodes,
+	}
+}
+
+func loginError(c echo.Context, err error) error {
+	var lockout *domain.LockoutError
+	if errors.As(err, &lockout) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(lockout.RetryAfter.Seconds())+1))
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+	if errors.Is(err, domain.ErrEmailNotVerified) || errors.Is(err, domain.ErrOrganizationInactive) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+}
+
+// Refresh godoc
+// @Summary Token Yenileme
+// @Description Refresh token ile yeni access token alır. Refresh token her kullanımda yenilenir;
+// kullanılmış bir refresh token tekrar gönderilirse oturumun tamamı iptal edilir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh Token"
+// @Success 200 {object} RefreshResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c echo.Context) error {
+	var req RefreshRequest
+	if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+	}
+
+	client := domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	tokens, err := h.service.Refresh(c.Request().Context(), req.RefreshToken, client)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, domain.ErrOrganizationInactive) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, RefreshResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 	})
 }
 
+// Logout godoc
+// @Summary Çıkış Yap
+// @Description Refresh token'ın ait olduğu oturumu sunucu tarafında kapatır. Oturuma ait access token'lar da geçersiz olur.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh Token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req RefreshRequest
+	if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "refresh_token is required"})
+	}
+
+	if err := h.service.Logout(c.Request().Context(), req.RefreshToken); err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "logged out"})
+}
+
 // Register godoc
 // @Summary Yeni Kullanıcı Kaydı
 // @Description Sisteme yeni bir kullanıcı ekler. En az bir adres girilmesi zorunludur.
+// Hesap, e-posta adresine gönderilen link ile doğrulanana kadar "verification_pending" durumunda kalır.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body RegisterRequest true "Kayıt Bilgileri (Adres Dahil)"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c echo.Context) error {
 	var req RegisterRequest
@@ -106,13 +304,17 @@ func (h *AuthHandler) Register(c echo.Context) error {
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
 		Addresses: req.Addresses, // Adresleri map ediyoruz
+		OrgID:     req.OrgID,
 	}
 
 	if err := h.service.Register(c.Request().Context(), user); err != nil {
+		if errors.Is(err, domain.ErrEmailInUse) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, map[string]string{"message": "user created"})
+	return c.JSON(http.StatusCreated, map[string]string{"message": "user created, verification email sent"})
 }
 
 // HealthCheck godoc
@@ -126,9 +328,23 @@ func (h *AuthHandler) HealthCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 }
 
+// JWKS godoc
+// @Summary Token İmza Anahtarları (JWKS)
+// @Description Token'ları doğrulamak için kullanılan public key'leri döner. Diğer servisler
+// token'ı auth servisine sormadan bu anahtarlarla doğrulayabilir.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} domain.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.keys.JWKS())
+}
+
 // ValidateToken godoc
 // @Summary Token Doğrulama
 // @Description Diğer mikroservislerin token kontrolü yapması için kullanılır.
+// Eski uç: servisler scope, süre ve rol bilgisini de dönen /oauth/introspect'i kullanmalıdır.
 // @Tags Auth
 // @Security BearerAuth
 // @Success 200 {object} map[string]interface{}
@@ -160,8 +376,21 @@ func (h *AuthHandler) ValidateToken(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user_id not found in token"})
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"valid":   true,
-		"user_id": userID,
-	})
+	email, _ := claims["email"].(string)
+	role, _ := claims["role"].(string)
+
+	result := map[string]interface{}{
+		"valid":       true,
+		"user_id":     userID,
+		"email":       email,
+		"role":        role,
+		"permissions": claimStrings(c, "permissions"),
+		"session_id":  claimString(c, "sid"),
+		"org":         domain.UserOrganization(claimString(c, "org")),
+	}
+	// Impersonation token'ında yerine geçen kişi
+	if act, ok := claims["act"]; ok {
+		result["act"] = act
+	}
+	return c.JSON(http.StatusOK, result)
 }
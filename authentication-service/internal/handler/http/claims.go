@@ -0,0 +1,63 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// tokenClaims JWT middleware'inin context'e koyduğu token'ın claim'lerini döner
+func tokenClaims(c echo.Context) jwt.MapClaims {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return jwt.MapClaims{}
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return jwt.MapClaims{}
+	}
+	return claims
+}
+
+// claimString claim'i string olarak okur, yoksa boş döner
+func claimString(c echo.Context, key string) string {
+	value, _ := tokenClaims(c)[key].(string)
+	return value
+}
+
+// claimStrings liste tipindeki claim'i okur ("permissions", "amr")
+func claimStrings(c echo.Context, key string) []string {
+	raw, _ := tokenClaims(c)[key].([]interface{})
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// isSuperAdmin isteği yapan organizasyon sınırı olmadan çalışabilir mi
+func isSuperAdmin(c echo.Context) bool {
+	return domain.IsSuperAdmin(claimString(c, "role"))
+}
+
+// callerOrganization isteği yapanın organizasyonu; eski token'larda varsayılan organizasyon
+func callerOrganization(c echo.Context) string {
+	return domain.UserOrganization(claimString(c, "org"))
+}
+
+// scopedOrganization listelemelerde kullanılacak organizasyon filtresi. Süper admin'ler
+// ?org= ile tek bir organizasyonu seçebilir, boş bırakırlarsa tümünü görür.
+func scopedOrganization(c echo.Context) string {
+	if isSuperAdmin(c) {
+		return c.QueryParam("org")
+	}
+	return callerOrganization(c)
+}
+
+// canAssignRole super_admin rolünü sadece süper admin'ler verebilir
+func canAssignRole(c echo.Context, role string) bool {
+	return !domain.IsSuperAdmin(role) || isSuperAdmin(c)
+}
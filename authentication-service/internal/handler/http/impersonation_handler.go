@@ -0,0 +1,152 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ImpersonationHandler struct {
+	service domain.ImpersonationService
+}
+
+func NewImpersonationHandler(service domain.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		service: service,
+	}
+}
+
+type StartImpersonationRequest struct {
+	Reason string `json:"reason"` // Örn. destek talebi numarası; audit kaydına yazılır
+}
+
+// Start godoc
+// @Summary Kullanıcının Yerine Geç
+// @Description Destek için kullanıcı adına kısa ömürlü, refresh edilemeyen bir access token verir. Token "act" claim'inde
+// isteği yapanı taşır ve bu sürede yapılan işlemler audit kayıtlarında impersonator ile işaretlenir. Bu token ile
+// yönetim uçları ve şifre, MFA, oturum, API anahtarı gibi hesap ayarları kullanılamaz.
+// Süper admin'lerin ve isteği yapandan fazla yetkisi olan kullanıcıların yerine geçilemez.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body StartImpersonationRequest true "Gerekçe"
+// @Success 201 {object} domain.ImpersonationResult
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Start(c echo.Context) error {
+	var req StartImpersonationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	result, err := h.service.Start(c.Request().Context(), domain.ImpersonationRequest{
+		Actor:       domain.Actor{Sub: claimString(c, "user_id"), Email: claimString(c, "email")},
+		Permissions: claimStrings(c, "permissions"),
+		UserID:      c.Param("id"),
+		Reason:      req.Reason,
+	}, domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()})
+	if err != nil {
+		return impersonationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// List godoc
+// @Summary Impersonation Oturumları
+// @Description Destek personelinin kullanıcıların yerine geçtiği oturumları en yeniden eskiye listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Yerine geçilen kullanıcı"
+// @Param impersonator_id query string false "Yerine geçen kişi"
+// @Param active query bool false "Sadece süresi dolmamış ve kapatılmamış oturumlar"
+// @Param page query int false "Sayfa (1'den başlar)"
+// @Param limit query int false "Sayfa boyutu (en fazla 100)"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {object} domain.ImpersonationPage
+// @Failure 400 {object} map[string]string
+// @Router /admin/impersonations [get]
+func (h *ImpersonationHandler) List(c echo.Context) error {
+	filter := domain.ImpersonationFilter{
+		OrgID:          scopedOrganization(c),
+		UserID:         c.QueryParam("user_id"),
+		ImpersonatorID: c.QueryParam("impersonator_id"),
+	}
+
+	active, err := queryBool(c, "active")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	filter.ActiveOnly = active != nil && *active
+	if filter.Page, err = queryInt(c, "page"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	page, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// End godoc
+// @Summary Impersonation Oturumunu Kapat
+// @Description Oturumu kapatır; verilen token her iki serviste de geçersiz olur.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/impersonations/{id} [delete]
+func (h *ImpersonationHandler) End(c echo.Context) error {
+	orgID := ""
+	if !isSuperAdmin(c) {
+		orgID = callerOrganization(c)
+	}
+	return h.end(c, c.Param("id"), orgID)
+}
+
+// EndCurrent godoc
+// @Summary Impersonation'dan Çık
+// @Description Destek personeli, kullandığı impersonation token'ı ile oturumu süresi dolmadan kapatır.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/impersonation/end [post]
+func (h *ImpersonationHandler) EndCurrent(c echo.Context) error {
+	if act, _ := tokenClaims(c)["act"].(map[string]interface{}); act == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": domain.ErrNotImpersonating.Error()})
+	}
+	return h.end(c, claimString(c, "sid"), "")
+}
+
+func (h *ImpersonationHandler) end(c echo.Context, sessionID, orgID string) error {
+	if err := h.service.End(c.Request().Context(), sessionID, orgID); err != nil {
+		return impersonationError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "impersonation ended"})
+}
+
+func impersonationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrImpersonationNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrImpersonationNotAllowed), errors.Is(err, domain.ErrOrganizationInactive):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrImpersonationReason):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
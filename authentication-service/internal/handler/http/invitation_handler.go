@@ -0,0 +1,203 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InvitationHandler struct {
+	service domain.InvitationService
+}
+
+func NewInvitationHandler(service domain.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		service: service,
+	}
+}
+
+type InviteRequest struct {
+	Email        string `json:"email"`
+	Role         string `json:"role"` // Boşsa "user"
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Organization string `json:"organization"` // Sadece süper admin; boşsa davet edenin organizasyonu
+}
+
+type AcceptInvitationRequest struct {
+	Token     string           `json:"token"`
+	Password  string           `json:"password"`
+	FirstName string           `json:"first_name"`
+	LastName  string           `json:"last_name"`
+	Addresses []domain.Address `json:"addresses"` // Zorunlu alan
+}
+
+// RegisterRoutes davetlinin kullandığı public uçları ekler; yönetim uçları main'de yetkiyle bağlanır
+func (h *InvitationHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/auth/invitations", h.Preview)
+	e.POST("/auth/invitations/accept", h.Accept)
+}
+
+// Invite godoc
+// @Summary Kullanıcı Davet Et
+// @Description E-posta adresine rolü önceden belirlenmiş tek kullanımlık davet linki gönderir.
+// Davetli şifresini ve adreslerini kendisi belirleyerek hesabını açar. Hesap davet edenin organizasyonunda açılır.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body InviteRequest true "Davet Bilgileri"
+// @Success 201 {object} domain.Invitation
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/invitations [post]
+func (h *InvitationHandler) Invite(c echo.Context) error {
+	var req InviteRequest
+	if err := c.Bind(&req); err != nil || req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "email is required"})
+	}
+	// Varsayılan dışında bir rolle davet etmek rol atamaktır; users:create tek başına yetmez
+	if req.Role != "" && req.Role != domain.RoleUser && !domain.HasPermission(claimStrings(c, "permissions"), domain.PermUsersManageRoles) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "permission required: " + domain.PermUsersManageRoles})
+	}
+	if !canAssignRole(c, req.Role) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": domain.ErrSuperAdminRole.Error()})
+	}
+	orgID := callerOrganization(c)
+	if isSuperAdmin(c) && req.Organization != "" {
+		orgID = req.Organization
+	}
+
+	invitation := &domain.Invitation{
+		Email:     req.Email,
+		Role:      req.Role,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		OrgID:     orgID,
+		InvitedBy: claimString(c, "user_id"),
+	}
+	if err := h.service.Invite(c.Request().Context(), invitation); err != nil {
+		return invitationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, invitation)
+}
+
+// List godoc
+// @Summary Davetler
+// @Description Davetleri en yeniden eskiye sayfalı listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, accepted, revoked veya expired"
+// @Param page query int false "Sayfa (1'den başlar)"
+// @Param limit query int false "Sayfa boyutu (en fazla 100)"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {object} domain.InvitationPage
+// @Failure 400 {object} map[string]string
+// @Router /admin/invitations [get]
+func (h *InvitationHandler) List(c echo.Context) error {
+	filter := domain.InvitationFilter{Status: c.QueryParam("status"), OrgID: scopedOrganization(c)}
+
+	var err error
+	if filter.Page, err = queryInt(c, "page"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	page, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// Revoke godoc
+// @Summary Daveti İptal Et
+// @Description Bekleyen daveti iptal eder; gönderilen link artık kullanılamaz.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/invitations/{id} [delete]
+func (h *InvitationHandler) Revoke(c echo.Context) error {
+	if err := h.service.Revoke(c.Request().Context(), c.Param("id"), scopedOrganization(c)); err != nil {
+		return invitationError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "invitation revoked"})
+}
+
+// Preview godoc
+// @Summary Davet Bilgisi
+// @Description Davet linkini açan kişiye hangi e-posta ve rolle hesap açılacağını gösterir.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Davet token'ı"
+// @Success 200 {object} domain.InvitationPreview
+// @Failure 400 {object} map[string]string
+// @Router /auth/invitations [get]
+func (h *InvitationHandler) Preview(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+
+	preview, err := h.service.Preview(c.Request().Context(), token)
+	if err != nil {
+		return invitationError(c, err)
+	}
+	return c.JSON(http.StatusOK, preview)
+}
+
+// Accept godoc
+// @Summary Daveti Kabul Et
+// @Description Davet token'ı ile şifre ve adres belirleyerek hesabı açar. E-posta link ile doğrulanmış sayılır;
+// hesap açıldıktan sonra /auth/login ile giriş yapılır.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body AcceptInvitationRequest true "Token, Şifre ve Adresler"
+// @Success 201 {object} domain.User
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /auth/invitations/accept [post]
+func (h *InvitationHandler) Accept(c echo.Context) error {
+	var req AcceptInvitationRequest
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token and password are required"})
+	}
+	if len(req.Addresses) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "at least one address is required"})
+	}
+
+	user, err := h.service.Accept(c.Request().Context(), req.Token, domain.InvitationAcceptance{
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Addresses: req.Addresses,
+	})
+	if err != nil {
+		return invitationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, user)
+}
+
+func invitationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvitationNotFound), errors.Is(err, domain.ErrOrganizationNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrEmailInUse), errors.Is(err, domain.ErrInvitationExists), errors.Is(err, domain.ErrInvitationClosed):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidInvitation), errors.Is(err, domain.ErrWeakPassword), errors.Is(err, domain.ErrRoleNotFound),
+		errors.Is(err, domain.ErrOrganizationInactive):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
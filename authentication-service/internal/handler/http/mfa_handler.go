@@ -0,0 +1,141 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MFAHandler struct {
+	service domain.MFAService
+	jwtAuth echo.MiddlewareFunc
+}
+
+func NewMFAHandler(service domain.MFAService, jwtAuth echo.MiddlewareFunc) *MFAHandler {
+	return &MFAHandler{
+		service: service,
+		jwtAuth: jwtAuth,
+	}
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (h *MFAHandler) RegisterRoutes(e *echo.Echo) {
+	g := e.Group("/auth/mfa", h.jwtAuth)
+	g.POST("/enroll", h.Enroll)
+	g.POST("/activate", h.Activate)
+	g.POST("/disable", h.Disable)
+	g.POST("/recovery-codes", h.RegenerateRecoveryCodes)
+}
+
+// Enroll godoc
+// @Summary MFA Kurulumunu Başlat
+// @Description Oturum açmış kullanıcı için yeni bir TOTP secret ve otpauth URI üretir (QR kod olarak gösterilebilir).
+// Secret, /auth/mfa/activate ile bir kod doğrulanana kadar aktif olmaz.
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.MFAEnrollment
+// @Failure 400 {object} map[string]string
+// @Router /auth/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c echo.Context) error {
+	enrollment, err := h.service.BeginEnrollment(c.Request().Context(), claimString(c, "user_id"))
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, enrollment)
+}
+
+// Activate godoc
+// @Summary MFA'yı Aktifleştir
+// @Description Authenticator'dan alınan kod ile kurulumu tamamlar. Kurtarma kodları yalnızca bu cevapta gösterilir.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP Kodu"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Router /auth/mfa/activate [post]
+func (h *MFAHandler) Activate(c echo.Context) error {
+	var req MFACodeRequest
+	if err := c.Bind(&req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+
+	codes, err := h.service.ActivateEnrollment(c.Request().Context(), claimString(c, "user_id"), req.Code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable godoc
+// @Summary MFA'yı Kapat
+// @Description Geçerli bir TOTP kodu ile iki adımlı doğrulamayı kapatır. MFA'nın zorunlu olduğu rollerde kapatılamaz.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP Kodu"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/mfa/disable [post]
+func (h *MFAHandler) Disable(c echo.Context) error {
+	var req MFACodeRequest
+	if err := c.Bind(&req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+
+	if err := h.service.Disable(c.Request().Context(), claimString(c, "user_id"), req.Code); err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "mfa disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Kurtarma Kodlarını Yenile
+// @Description Geçerli bir TOTP kodu ile yeni kurtarma kodları üretir; eski kodlar geçersiz olur.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP Kodu"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Router /auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	var req MFACodeRequest
+	if err := c.Bind(&req); err != nil || req.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(c.Request().Context(), claimString(c, "user_id"), req.Code)
+	if err != nil {
+		return mfaError(c, err)
+	}
+	return c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func mfaError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrMFARequiredForRole):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidMFACode),
+		errors.Is(err, domain.ErrMFANotEnrolled),
+		errors.Is(err, domain.ErrMFAAlreadyEnabled),
+		errors.Is(err, domain.ErrMFANotEnabled):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
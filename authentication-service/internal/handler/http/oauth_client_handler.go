@@ -0,0 +1,122 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OAuthClientHandler struct {
+	service domain.OAuthClientService
+}
+
+func NewOAuthClientHandler(service domain.OAuthClientService) *OAuthClientHandler {
+	return &OAuthClientHandler{
+		service: service,
+	}
+}
+
+type RegisterOAuthClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"` // https (yerel geliştirmede http://localhost) ve fragment'sız olmalı
+	Public       bool     `json:"public"`        // SPA ve mobil uygulamalar: secret verilmez
+	FirstParty   bool     `json:"first_party"`   // Kendi uygulamalarımız: token kullanıcının rol ve yetkilerini taşır
+	Scopes       []string `json:"scopes"`        // Servis istemcileri (client_credentials): token:introspect, sessions:revoked
+}
+
+// RegisterOAuthClientResponse client_secret sadece bu cevapta döner, sonradan görüntülenemez
+type RegisterOAuthClientResponse struct {
+	*domain.OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Register godoc
+// @Summary OIDC İstemcisi Kaydet
+// @Description Yeni bir OAuth2/OIDC istemcisi oluşturur. Gizli istemcilerin client_secret'ı sadece bu cevapta gösterilir.
+// scopes verilen gizli istemciler client_credentials grant'i ile servisler arası token alabilir.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterOAuthClientRequest true "İstemci"
+// @Success 201 {object} RegisterOAuthClientResponse
+// @Failure 400 {object} map[string]string
+// @Router /admin/oauth/clients [post]
+func (h *OAuthClientHandler) Register(c echo.Context) error {
+	var req RegisterOAuthClientRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	client := &domain.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Public:       req.Public,
+		FirstParty:   req.FirstParty,
+		Scopes:       req.Scopes,
+		CreatedBy:    claimString(c, "user_id"),
+	}
+	secret, err := h.service.Register(c.Request().Context(), client)
+	if err != nil {
+		return oauthClientError(c, err)
+	}
+	return c.JSON(http.StatusCreated, RegisterOAuthClientResponse{OAuthClient: client, ClientSecret: secret})
+}
+
+// List godoc
+// @Summary OIDC İstemcileri
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.OAuthClient
+// @Router /admin/oauth/clients [get]
+func (h *OAuthClientHandler) List(c echo.Context) error {
+	clients, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, clients)
+}
+
+// Get godoc
+// @Summary OIDC İstemci Detayı
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "client_id"
+// @Success 200 {object} domain.OAuthClient
+// @Failure 404 {object} map[string]string
+// @Router /admin/oauth/clients/{id} [get]
+func (h *OAuthClientHandler) Get(c echo.Context) error {
+	client, err := h.service.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return oauthClientError(c, err)
+	}
+	return c.JSON(http.StatusOK, client)
+}
+
+// Delete godoc
+// @Summary OIDC İstemcisini Sil
+// @Description İstemciyi siler ve istemciye verilmiş tüm oturumları kapatır.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "client_id"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/oauth/clients/{id} [delete]
+func (h *OAuthClientHandler) Delete(c echo.Context) error {
+	if err := h.service.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return oauthClientError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "oauth client deleted"})
+}
+
+func oauthClientError(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrOAuthClientNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,368 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"bytes"
+	_ "embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/authorize.html
+var authorizeTemplateSource string
+
+var scopeLabels = map[string]string{
+	domain.ScopeOpenID:        "Kimliğinizi doğrulama",
+	domain.ScopeProfile:       "Adınız ve soyadınız",
+	domain.ScopeEmail:         "E-posta adresiniz",
+	domain.ScopeOfflineAccess: "Siz uygulamayı kullanmıyorken de erişimin sürmesi",
+}
+
+var authorizeTemplate = template.Must(template.New("authorize").Funcs(template.FuncMap{
+	"scopeLabel": func(scope string) string { return scopeLabels[scope] },
+}).Parse(authorizeTemplateSource))
+
+type OIDCHandler struct {
+	service       domain.OIDCService
+	auth          domain.AuthService
+	delegatedAuth echo.MiddlewareFunc
+}
+
+// NewOIDCHandler delegatedAuth üçüncü taraf istemcilerin token'larını da kabul eden JWT middleware'idir
+func NewOIDCHandler(service domain.OIDCService, auth domain.AuthService, delegatedAuth echo.MiddlewareFunc) *OIDCHandler {
+	return &OIDCHandler{
+		service:       service,
+		auth:          auth,
+		delegatedAuth: delegatedAuth,
+	}
+}
+
+// authorizePage giriş formunun şablon verisi
+type authorizePage struct {
+	Fatal      bool // İstemci veya redirect_uri geçersiz: form gösterilmez, yönlendirme yapılmaz
+	Error      string
+	ClientName string
+	Scopes     []string
+	Request    domain.AuthorizationRequest
+	Email      string
+	MFAToken   string // Doluysa şifre adımı geçildi, kod isteniyor
+}
+
+func (h *OIDCHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/.well-known/openid-configuration", h.Discovery)
+	e.GET("/oauth/authorize", h.Authorize)
+	e.POST("/oauth/authorize", h.AuthorizeSubmit)
+	e.POST("/oauth/token", h.Token)
+	e.POST("/oauth/introspect", h.Introspect)
+	e.GET("/oauth/userinfo", h.UserInfo, h.delegatedAuth)
+	e.POST("/oauth/userinfo", h.UserInfo, h.delegatedAuth)
+}
+
+// Discovery godoc
+// @Summary OpenID Connect Discovery
+// @Description İstemcilerin endpoint adreslerini, desteklenen scope'ları ve imza algoritmalarını otomatik bulması için kullanılır.
+// @Tags OIDC
+// @Produce json
+// @Success 200 {object} domain.OIDCDiscovery
+// @Router /.well-known/openid-configuration [get]
+func (h *OIDCHandler) Discovery(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, h.service.Discovery())
+}
+
+// Authorize godoc
+// @Summary Yetkilendirme (Giriş Sayfası)
+// @Description Authorization code + PKCE akışını başlatır ve giriş formunu gösterir. Sadece response_type=code ve
+// code_challenge_method=S256 desteklenir; scope "openid" içermelidir. Başarılı girişte redirect_uri'ye code ve state ile dönülür.
+// @Tags OIDC
+// @Produce html
+// @Param response_type query string true "code"
+// @Param client_id query string true "İstemci ID"
+// @Param redirect_uri query string true "Kayıtlı yönlendirme adresi"
+// @Param scope query string true "Örn. openid profile email"
+// @Param state query string false "İstemcinin CSRF değeri, aynen geri döner"
+// @Param nonce query string false "ID token'a yazılır"
+// @Param code_challenge query string true "BASE64URL(SHA256(code_verifier))"
+// @Param code_challenge_method query string true "S256"
+// @Success 200 {string} string "Giriş formu"
+// @Failure 302 {string} string "redirect_uri?error=..."
+// @Failure 400 {string} string "Geçersiz istemci veya redirect_uri"
+// @Router /oauth/authorize [get]
+func (h *OIDCHandler) Authorize(c echo.Context) error {
+	req := authorizationRequest(c)
+	client, scopes, err := h.service.ValidateAuthorization(c.Request().Context(), req)
+	if err != nil {
+		return h.authorizeError(c, client, req, err)
+	}
+	return h.renderAuthorize(c, http.StatusOK, authorizePage{ClientName: client.Name, Scopes: scopes, Request: req})
+}
+
+// AuthorizeSubmit godoc
+// @Summary Yetkilendirme (Form Gönderimi)
+// @Description Giriş formunu işler. İki adımlı doğrulama açıksa kod adımı gösterilir. Başarılı girişte
+// tek kullanımlık kod üretilip redirect_uri'ye yönlendirilir; "Vazgeç" seçilirse error=access_denied döner.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Success 302 {string} string "redirect_uri?code=...&state=..."
+// @Failure 400 {string} string "Geçersiz istemci veya redirect_uri"
+// @Router /oauth/authorize [post]
+func (h *OIDCHandler) AuthorizeSubmit(c echo.Context) error {
+	ctx := c.Request().Context()
+	req := authorizationRequest(c)
+	client, scopes, err := h.service.ValidateAuthorization(ctx, req)
+	if err != nil {
+		return h.authorizeError(c, client, req, err)
+	}
+	if c.FormValue("action") == "cancel" {
+		return h.authorizeError(c, client, req, domain.NewOAuthError(domain.OAuthAccessDenied, "user cancelled the login"))
+	}
+
+	page := authorizePage{ClientName: client.Name, Scopes: scopes, Request: req, Email: c.FormValue("email")}
+	info := domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+
+	var result *domain.LoginResult
+	amr := []string{"pwd"}
+	if mfaToken := c.FormValue("mfa_token"); mfaToken != "" {
+		amr = []string{"pwd", "otp"}
+		result, err = h.auth.AuthenticateMFA(ctx, mfaToken, c.FormValue("code"), c.FormValue("recovery_code"), info)
+		// Kod hatalıysa aynı challenge ile tekrar denenebilir; challenge geçersizse şifre adımına dönülür
+		if err != nil && !errors.Is(err, domain.ErrInvalidMFAToken) {
+			page.MFAToken = mfaToken
+		}
+	} else {
+		result, err = h.auth.Authenticate(ctx, page.Email, c.FormValue("password"), info)
+	}
+	if err != nil {
+		page.Error = authorizeLoginMessage(err)
+		return h.renderAuthorize(c, http.StatusOK, page)
+	}
+	if result.MFARequired {
+		page.MFAToken = result.MFAToken
+		return h.renderAuthorize(c, http.StatusOK, page)
+	}
+
+	code, err := h.service.IssueCode(ctx, req, result.User, amr)
+	if err != nil {
+		return h.authorizeError(c, client, req, err)
+	}
+	return c.Redirect(http.StatusFound, authorizationRedirect(req, url.Values{"code": {code}}))
+}
+
+// Token godoc
+// @Summary Token Endpoint
+// @Description authorization_code grant'i ile kodu (code_verifier ile) access token ve ID token'a çevirir;
+// refresh_token grant'i ile token yeniler. Refresh token sadece offline_access scope'u onaylandıysa verilir.
+// client_credentials grant'i servis istemcisine kullanıcıdan bağımsız, scope'lu bir token verir.
+// Gizli istemciler client_secret_basic veya client_secret_post, public istemciler sadece client_id kullanır.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code, refresh_token veya client_credentials"
+// @Param code formData string false "Yetkilendirme kodu"
+// @Param redirect_uri formData string false "Yetkilendirmede kullanılan redirect_uri"
+// @Param code_verifier formData string false "PKCE doğrulayıcısı"
+// @Param refresh_token formData string false "Refresh token"
+// @Param scope formData string false "client_credentials: istenen servis scope'ları (boşsa hepsi)"
+// @Param client_id formData string false "İstemci ID (Basic auth kullanılmıyorsa)"
+// @Param client_secret formData string false "İstemci secret'ı (client_secret_post)"
+// @Success 200 {object} domain.OIDCTokenResponse
+// @Failure 400 {object} domain.OAuthError
+// @Failure 401 {object} domain.OAuthError
+// @Router /oauth/token [post]
+func (h *OIDCHandler) Token(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+
+	clientID, clientSecret, basicAuth := clientCredentials(c)
+	req := domain.TokenRequest{
+		GrantType:    c.FormValue("grant_type"),
+		Code:         c.FormValue("code"),
+		RedirectURI:  c.FormValue("redirect_uri"),
+		CodeVerifier: c.FormValue("code_verifier"),
+		RefreshToken: c.FormValue("refresh_token"),
+		Scope:        c.FormValue("scope"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}
+
+	info := domain.ClientInfo{IP: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	resp, err := h.service.Token(c.Request().Context(), req, info)
+	if err != nil {
+		return oauthError(c, err, basicAuth)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Introspect godoc
+// @Summary Token Introspection (RFC 7662)
+// @Description Access token'ın aktif olup olmadığını, scope'larını, süresini, sahibini ve rolünü döner.
+// Sadece token:introspect scope'una sahip servis istemcileri (client_secret_basic/post) çağırabilir.
+// Geçersiz, süresi dolmuş veya oturumu iptal edilmiş token'lar için active=false döner.
+// @Tags OIDC
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Sorgulanacak token"
+// @Param token_type_hint formData string false "access_token (refresh token'lar desteklenmez)"
+// @Success 200 {object} domain.TokenIntrospection
+// @Failure 401 {object} domain.OAuthError
+// @Failure 403 {object} domain.OAuthError
+// @Router /oauth/introspect [post]
+func (h *OIDCHandler) Introspect(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	clientID, clientSecret, basicAuth := clientCredentials(c)
+	if c.FormValue("token") == "" {
+		return c.JSON(http.StatusBadRequest, domain.NewOAuthError(domain.OAuthInvalidRequest, "token is required"))
+	}
+
+	result, err := h.service.Introspect(c.Request().Context(), clientID, clientSecret, c.FormValue("token"))
+	var oauthErr *domain.OAuthError
+	if errors.As(err, &oauthErr) && oauthErr.Code == domain.OAuthUnauthorizedClient {
+		return c.JSON(http.StatusForbidden, oauthErr)
+	}
+	if err != nil {
+		return oauthError(c, err, basicAuth)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// clientCredentials istemci kimliğini Basic header'dan (client_secret_basic) ya da gövdeden okur.
+// RFC 6749 2.3.1'e göre Basic header'daki değerler form-urlencoded gelir.
+func clientCredentials(c echo.Context) (string, string, bool) {
+	if id, secret, ok := c.Request().BasicAuth(); ok {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+		return id, secret, true
+	}
+	return c.FormValue("client_id"), c.FormValue("client_secret"), false
+}
+
+// oauthError OAuth hata cevabını döner; istemci doğrulama hataları 401, diğerleri 400'dür
+func oauthError(c echo.Context, err error, basicAuth bool) error {
+	var oauthErr *domain.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.Printf("OAuth endpoint error: %v", err)
+		return c.JSON(http.StatusInternalServerError, domain.NewOAuthError(domain.OAuthServerError, ""))
+	}
+	if oauthErr.Code == domain.OAuthInvalidClient {
+		if basicAuth {
+			c.Response().Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		}
+		return c.JSON(http.StatusUnauthorized, oauthErr)
+	}
+	return c.JSON(http.StatusBadRequest, oauthErr)
+}
+
+// UserInfo godoc
+// @Summary UserInfo
+// @Description OIDC istemcisine verilen access token ile kullanıcının onaylanan scope'lara göre bilgilerini döner.
+// Token "openid" scope'unu taşımalıdır.
+// @Tags OIDC
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /oauth/userinfo [get]
+func (h *OIDCHandler) UserInfo(c echo.Context) error {
+	scopes := strings.Fields(claimString(c, "scope"))
+	if !slices.Contains(scopes, domain.ScopeOpenID) {
+		c.Response().Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient_scope"})
+	}
+
+	claims, err := h.service.UserInfo(c.Request().Context(), claimString(c, "user_id"), scopes)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid_token"})
+	}
+	return c.JSON(http.StatusOK, claims)
+}
+
+// authorizeError istemci ve redirect_uri doğrulandıysa hatayı redirect_uri'ye iletir, aksi halde sayfada gösterir
+func (h *OIDCHandler) authorizeError(c echo.Context, client *domain.OAuthClient, req domain.AuthorizationRequest, err error) error {
+	var oauthErr *domain.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.Printf("OIDC authorize error: %v", err)
+		oauthErr = domain.NewOAuthError(domain.OAuthServerError, "")
+	}
+	if client == nil {
+		return h.renderAuthorize(c, http.StatusBadRequest, authorizePage{Fatal: true, Error: "Uygulama veya yönlendirme adresi tanınmıyor."})
+	}
+
+	params := url.Values{"error": {oauthErr.Code}}
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	return c.Redirect(http.StatusFound, authorizationRedirect(req, params))
+}
+
+func (h *OIDCHandler) renderAuthorize(c echo.Context, status int, page authorizePage) error {
+	var buf bytes.Buffer
+	if err := authorizeTemplate.Execute(&buf, page); err != nil {
+		return c.String(http.StatusInternalServerError, "template error")
+	}
+	// Giriş formu başka sitelerde çerçeve içinde gösterilemez (clickjacking) ve cache'lenmez
+	c.Response().Header().Set("X-Frame-Options", "DENY")
+	c.Response().Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.HTMLBlob(status, buf.Bytes())
+}
+
+func authorizationRequest(c echo.Context) domain.AuthorizationRequest {
+	return domain.AuthorizationRequest{
+		ResponseType:        c.FormValue("response_type"),
+		ClientID:            c.FormValue("client_id"),
+		RedirectURI:         c.FormValue("redirect_uri"),
+		Scope:               c.FormValue("scope"),
+		State:               c.FormValue("state"),
+		Nonce:               c.FormValue("nonce"),
+		CodeChallenge:       c.FormValue("code_challenge"),
+		CodeChallengeMethod: c.FormValue("code_challenge_method"),
+	}
+}
+
+// authorizationRedirect parametreleri (ve varsa state'i) kayıtlı redirect_uri'nin query'sine ekler
+func authorizationRedirect(req domain.AuthorizationRequest, params url.Values) string {
+	target, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		return req.RedirectURI
+	}
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	target.RawQuery = query.Encode()
+	return target.String()
+}
+
+// authorizeLoginMessage giriş formunda gösterilecek mesaj; hesabın durumu hakkında login ile aynı bilgiyi verir
+func authorizeLoginMessage(err error) string {
+	var lockout *domain.LockoutError
+	switch {
+	case errors.As(err, &lockout):
+		return "Çok fazla hatalı deneme yapıldı. Lütfen daha sonra tekrar deneyin."
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		return "E-posta adresiniz henüz doğrulanmamış."
+	case errors.Is(err, domain.ErrOrganizationInactive):
+		return "Kurumunuzun hesabı pasif durumda."
+	case errors.Is(err, domain.ErrMFAEnrollmentNeeded):
+		return "Hesabınız için iki adımlı doğrulama kurulmalı. Lütfen önce uygulamamız üzerinden giriş yapın."
+	case errors.Is(err, domain.ErrInvalidMFAToken):
+		return "Oturumun süresi doldu, lütfen tekrar giriş yapın."
+	case errors.Is(err, domain.ErrInvalidMFACode):
+		return "Doğrulama kodu hatalı."
+	default:
+		return "E-posta veya şifre hatalı."
+	}
+}
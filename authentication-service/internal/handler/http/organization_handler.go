@@ -0,0 +1,133 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OrganizationHandler struct {
+	service domain.OrganizationService
+}
+
+func NewOrganizationHandler(service domain.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		service: service,
+	}
+}
+
+type CreateOrganizationRequest struct {
+	ID   string `json:"id"` // Küçük harf, rakam ve tireden oluşan slug
+	Name string `json:"name"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name   string `json:"name"`   // Boşsa mevcut ad korunur
+	Active *bool  `json:"active"` // Gönderilmezse durum değişmez
+}
+
+// List godoc
+// @Summary Organizasyonlar
+// @Description Tüm organizasyonları (belediyeleri) listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Organization
+// @Router /admin/organizations [get]
+func (h *OrganizationHandler) List(c echo.Context) error {
+	orgs, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, orgs)
+}
+
+// Get godoc
+// @Summary Organizasyon Detayı
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organizasyon ID"
+// @Success 200 {object} domain.Organization
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{id} [get]
+func (h *OrganizationHandler) Get(c echo.Context) error {
+	org, err := h.service.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, org)
+}
+
+// Create godoc
+// @Summary Organizasyon Oluştur
+// @Description Yeni bir belediye tanımlar. ID token'a yazıldığı için sonradan değiştirilemez.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrganizationRequest true "Organizasyon"
+// @Success 201 {object} domain.Organization
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/organizations [post]
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	var req CreateOrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	org := &domain.Organization{ID: req.ID, Name: req.Name}
+	if err := h.service.Create(c.Request().Context(), org); err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusCreated, org)
+}
+
+// Update godoc
+// @Summary Organizasyon Güncelle
+// @Description Organizasyonun adını veya aktifliğini değiştirir. Pasif organizasyonun kullanıcıları
+// giriş yapamaz ve token yenileyemez; mevcut access token'lar süreleri dolana kadar geçerlidir.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organizasyon ID"
+// @Param request body UpdateOrganizationRequest true "Organizasyon"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/organizations/{id} [put]
+func (h *OrganizationHandler) Update(c echo.Context) error {
+	var req UpdateOrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	ctx := c.Request().Context()
+	existing, err := h.service.Get(ctx, c.Param("id"))
+	if err != nil {
+		return organizationError(c, err)
+	}
+	org := &domain.Organization{Name: req.Name, Active: existing.Active}
+	if req.Active != nil {
+		org.Active = *req.Active
+	}
+	if err := h.service.Update(ctx, existing.ID, org); err != nil {
+		return organizationError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "organization updated"})
+}
+
+func organizationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrganizationNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrOrganizationExists):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PasswordHandler struct {
+	service domain.PasswordService
+}
+
+func NewPasswordHandler(service domain.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		service: service,
+	}
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (h *PasswordHandler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/auth/password/forgot", h.ForgotPassword)
+	e.POST("/auth/password/reset", h.ResetPassword)
+}
+
+// ForgotPassword godoc
+// @Summary Şifremi Unuttum
+// @Description Hesap varsa e-posta adresine tek kullanımlık şifre sıfırlama linki gönderir.
+// Hesabın varlığını sızdırmamak için her durumda aynı cevap döner.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "E-posta"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(c echo.Context) error {
+	var req ForgotPasswordRequest
+	if err := c.Bind(&req); err != nil || req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "email is required"})
+	}
+
+	if err := h.service.ForgotPassword(c.Request().Context(), req.Email); err != nil {
+		c.Logger().Error("forgot password: ", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "if the account exists, a reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary Şifre Sıfırla
+// @Description E-posta ile gelen token ile yeni şifre belirler. Token tek kullanımlıktır;
+// başarılı sıfırlamadan sonra kullanıcının tüm oturumları kapatılır.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Token ve Yeni Şifre"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/password/reset [post]
+func (h *PasswordHandler) ResetPassword(c echo.Context) error {
+	var req ResetPasswordRequest
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token and new_password are required"})
+	}
+
+	if err := h.service.ResetPassword(c.Request().Context(), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, domain.ErrInvalidOneTimeToken) || errors.Is(err, domain.ErrWeakPassword) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "password has been reset"})
+}
+
+// ChangePassword godoc
+// @Summary Şifre Değiştir
+// @Description Oturum açmış kullanıcının şifresini mevcut şifre onayıyla değiştirir.
+// İsteği yapan oturum açık kalır, diğer oturumlar kapatılır.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Mevcut ve Yeni Şifre"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /users/me/password [put]
+func (h *PasswordHandler) ChangePassword(c echo.Context) error {
+	var req ChangePasswordRequest
+	if err := c.Bind(&req); err != nil || req.CurrentPassword == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "current_password and new_password are required"})
+	}
+
+	err := h.service.ChangePassword(c.Request().Context(), claimString(c, "user_id"), req.CurrentPassword, req.NewPassword, claimString(c, "sid"))
+	switch {
+	case err == nil:
+		return c.JSON(http.StatusOK, map[string]string{"message": "password changed"})
+	case errors.Is(err, domain.ErrWrongPassword):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrWeakPassword):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PrivacyHandler struct {
+	service domain.PrivacyService
+}
+
+func NewPrivacyHandler(service domain.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		service: service,
+	}
+}
+
+type EraseRequest struct {
+	Password string `json:"password"`
+}
+
+// Export godoc
+// @Summary Verilerimi Dışa Aktar
+// @Description KVKK/GDPR kapsamında hesap bilgilerini ve waste servisindeki atık kayıtları, talepler ve resimleri tek ZIP olarak döner.
+// @Tags Users
+// @Produce application/zip
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /users/me/export [get]
+func (h *PrivacyHandler) Export(c echo.Context) error {
+	var buf bytes.Buffer
+	err := h.service.Export(c.Request().Context(), claimString(c, "user_id"), &buf)
+	if err != nil {
+		return privacyError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="my-data.zip"`)
+	return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// Erase godoc
+// @Summary Hesabımı ve Verilerimi Sil
+// @Description Şifre onayıyla waste servisindeki resimleri siler, atık kayıtlarını anonimleştirir ve hesabı kalıcı olarak siler.
+// Waste servisine ulaşılamazsa hesap silinmez ve talep tekrarlanabilir.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body EraseRequest true "Şifre"
+// @Success 200 {object} domain.ErasureRequest
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Router /users/me/erase [post]
+func (h *PrivacyHandler) Erase(c echo.Context) error {
+	var req EraseRequest
+	if err := c.Bind(&req); err != nil || req.Password == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "password is required"})
+	}
+
+	erasure, err := h.service.Erase(c.Request().Context(), claimString(c, "user_id"), req.Password)
+	if err != nil {
+		return privacyError(c, err)
+	}
+	return c.JSON(http.StatusOK, erasure)
+}
+
+// ListErasures godoc
+// @Summary Silme Talepleri
+// @Description Yerine getirilen ve başarısız olan KVKK/GDPR silme taleplerini en yeniden eskiye listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Kayıt sayısı (varsayılan 50)"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {array} domain.ErasureRequest
+// @Router /admin/privacy/erasures [get]
+func (h *PrivacyHandler) ListErasures(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if limit == 0 {
+		limit = 50
+	}
+	if err != nil || limit < 1 || limit > 200 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 200"})
+	}
+
+	erasures, err := h.service.ListErasures(c.Request().Context(), scopedOrganization(c), int64(limit))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, erasures)
+}
+
+func privacyError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrWrongPassword):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrWasteServiceUnavailable):
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
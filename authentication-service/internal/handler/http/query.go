@@ -0,0 +1,55 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// queryInt boş parametre için 0 döner
+func queryInt(c echo.Context, key string) (int, error) {
+	value := c.QueryParam(key)
+	if value == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return i, nil
+}
+
+// queryBool parametre verilmemişse nil döner (filtre uygulanmaz)
+func queryBool(c echo.Context, key string) (*bool, error) {
+	value := c.QueryParam(key)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &b, nil
+}
+
+// queryTime RFC3339 veya YYYY-MM-DD kabul eder. Sadece tarih verilmiş bir bitiş değeri
+// o günün sonuna çekilir ki "created_to=2024-05-01" o günü de kapsasın.
+func queryTime(c echo.Context, key string, endOfDay bool) (*time.Time, error) {
+	value := c.QueryParam(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
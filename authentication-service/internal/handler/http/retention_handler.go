@@ -0,0 +1,141 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RetentionHandler struct {
+	service domain.RetentionService
+	users   domain.UserService
+}
+
+func NewRetentionHandler(service domain.RetentionService, users domain.UserService) *RetentionHandler {
+	return &RetentionHandler{
+		service: service,
+		users:   users,
+	}
+}
+
+// ListDeleted godoc
+// @Summary Silinmiş Kullanıcılar
+// @Description Soft-delete edilmiş kullanıcıları /users ile aynı sayfalama, filtre ve sıralama parametreleriyle listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Sayfa (1'den başlar)"
+// @Param limit query int false "Sayfa boyutu (en fazla 100)"
+// @Param q query string false "Ad, soyad veya e-postada arama"
+// @Param sort query string false "Sıralama alanı"
+// @Param order query string false "asc veya desc"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {object} domain.UserPage
+// @Failure 400 {object} map[string]string
+// @Router /admin/users/deleted [get]
+func (h *RetentionHandler) ListDeleted(c echo.Context) error {
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	deleted := true
+	filter.Deleted = &deleted
+
+	page, err := h.users.List(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, page)
+}
+
+// Restore godoc
+// @Summary Silinmiş Kullanıcıyı Geri Al
+// @Description Soft delete'i geri alır ve kullanıcıyı tekrar aktif yapar.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/users/{id}/restore [post]
+func (h *RetentionHandler) Restore(c echo.Context) error {
+	if err := h.service.Restore(c.Request().Context(), c.Param("id")); err != nil {
+		return retentionError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "user restored"})
+}
+
+// Purge godoc
+// @Summary Kullanıcıyı Kalıcı Sil
+// @Description Soft-delete edilmiş kullanıcıyı oturumları ve token'larıyla birlikte kalıcı olarak siler. Geri alınamaz.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/users/{id}/purge [delete]
+func (h *RetentionHandler) Purge(c echo.Context) error {
+	if err := h.service.Purge(c.Request().Context(), c.Param("id")); err != nil {
+		return retentionError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "user purged"})
+}
+
+// Run godoc
+// @Summary Saklama Temizliğini Çalıştır
+// @Description Saklama süresini aşmış silinmiş kullanıcıları hemen temizler ve raporu döner.
+// Tüm organizasyonları kapsadığı için sadece süper admin çalıştırabilir.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} domain.RetentionReport
+// @Failure 500 {object} domain.RetentionReport
+// @Router /admin/users/retention/run [post]
+func (h *RetentionHandler) Run(c echo.Context) error {
+	report, err := h.service.Run(c.Request().Context(), domain.RetentionTriggerManual)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
+// Reports godoc
+// @Summary Saklama Temizliği Raporları
+// @Description Zamanlanmış ve elle tetiklenen temizliklerin raporlarını en yeniden eskiye döner.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Rapor sayısı (varsayılan 20)"
+// @Success 200 {array} domain.RetentionReport
+// @Router /admin/users/retention/reports [get]
+func (h *RetentionHandler) Reports(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if limit == 0 {
+		limit = 20
+	}
+	if err != nil || limit < 1 || limit > 100 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "limit must be between 1 and 100"})
+	}
+
+	reports, err := h.service.Reports(c.Request().Context(), int64(limit))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, reports)
+}
+
+func retentionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrUserNotDeleted):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoleHandler struct {
+	service domain.RoleService
+}
+
+func NewRoleHandler(service domain.RoleService) *RoleHandler {
+	return &RoleHandler{
+		service: service,
+	}
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// List godoc
+// @Summary Rolleri Listele
+// @Description Tanımlı tüm rolleri ve yetkilerini döner.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Role
+// @Router /admin/roles [get]
+func (h *RoleHandler) List(c echo.Context) error {
+	roles, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, roles)
+}
+
+// Permissions godoc
+// @Summary Yetki Listesi
+// @Description Rollere atanabilecek yetkileri döner. "*" tüm yetkiler, "kaynak:*" bir kaynaktaki tüm işlemler anlamına gelir.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} string
+// @Router /admin/roles/permissions [get]
+func (h *RoleHandler) Permissions(c echo.Context) error {
+	return c.JSON(http.StatusOK, domain.Permissions)
+}
+
+// Get godoc
+// @Summary Rol Detayı
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Rol adı"
+// @Success 200 {object} domain.Role
+// @Failure 404 {object} map[string]string
+// @Router /admin/roles/{name} [get]
+func (h *RoleHandler) Get(c echo.Context) error {
+	role, err := h.service.Get(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return roleError(c, err)
+	}
+	return c.JSON(http.StatusOK, role)
+}
+
+// Create godoc
+// @Summary Rol Oluştur
+// @Description Yeni bir rol tanımlar. Rol adı küçük harf, rakam ve alt çizgiden oluşmalıdır.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RoleRequest true "Rol"
+// @Success 201 {object} domain.Role
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/roles [post]
+func (h *RoleHandler) Create(c echo.Context) error {
+	var req RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	role := &domain.Role{Name: req.Name, Description: req.Description, Permissions: req.Permissions}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	if err := h.service.Create(c.Request().Context(), role); err != nil {
+		return roleError(c, err)
+	}
+	return c.JSON(http.StatusCreated, role)
+}
+
+// Update godoc
+// @Summary Rol Güncelle
+// @Description Rolün açıklamasını ve yetkilerini günceller. Değişiklik kullanıcıların bir sonraki token yenilemesinde geçerli olur.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Rol adı"
+// @Param request body RoleRequest true "Rol"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/roles/{name} [put]
+func (h *RoleHandler) Update(c echo.Context) error {
+	var req RoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
+	}
+
+	role := &domain.Role{Description: req.Description, Permissions: req.Permissions}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	if err := h.service.Update(c.Request().Context(), c.Param("name"), role); err != nil {
+		return roleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "role updated"})
+}
+
+// Delete godoc
+// @Summary Rol Sil
+// @Description Kullanıcılara atanmış roller ve sistem rolleri silinemez.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Rol adı"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /admin/roles/{name} [delete]
+func (h *RoleHandler) Delete(c echo.Context) error {
+	if err := h.service.Delete(c.Request().Context(), c.Param("name")); err != nil {
+		return roleError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "role deleted"})
+}
+
+func roleError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrRoleNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrRoleExists),
+		errors.Is(err, domain.ErrRoleInUse),
+		errors.Is(err, domain.ErrSystemRole):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrUnknownPermission),
+		errors.Is(err, domain.ErrInvalidRoleName):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SessionHandler struct {
+	service domain.SessionService
+}
+
+func NewSessionHandler(service domain.SessionService) *SessionHandler {
+	return &SessionHandler{
+		service: service,
+	}
+}
+
+// ListMine godoc
+// @Summary Oturumlarım
+// @Description Hesabın açık olduğu cihazları (user agent, IP, açılış ve son görülme zamanı) listeler. İsteği yapan oturum "current" ile işaretlenir.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Session
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) ListMine(c echo.Context) error {
+	sessions, err := h.service.List(c.Request().Context(), claimString(c, "user_id"), claimString(c, "sid"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeMine godoc
+// @Summary Oturumu Kapat
+// @Description Kendi oturumlarından birini (örn. kaybolan telefon) kapatır. Oturuma ait access ve refresh token'lar geçersiz olur.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) RevokeMine(c echo.Context) error {
+	if err := h.service.Revoke(c.Request().Context(), claimString(c, "user_id"), c.Param("id")); err != nil {
+		return sessionError(c, err)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "session revoked"})
+}
+
+// ListUser godoc
+// @Summary Kullanıcının Oturumları
+// @Description Bir kullanıcının açık oturumlarını listeler.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} domain.Session
+// @Router /admin/users/{id}/sessions [get]
+func (h *SessionHandler) ListUser(c echo.Context) error {
+	sessions, err := h.service.List(c.Request().Context(), c.Param("id"), claimString(c, "sid"))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeUser godoc
+// @Summary Kullanıcının Tüm Oturumlarını Kapat
+// @Description Kullanıcının bütün cihazlardaki oturumlarını kapatır; tekrar giriş yapması gerekir.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Router /admin/users/{id}/sessions [delete]
+func (h *SessionHandler) RevokeUser(c echo.Context) error {
+	if err := h.service.RevokeAll(c.Request().Context(), c.Param("id")); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "all sessions revoked"})
+}
+
+// Revoked godoc
+// @Summary İptal Edilen Oturumlar
+// @Description Token'ları lokal doğrulayan servislerin (waste-service) iptal edilen oturumları öğrenmesi için akış.
+// Sadece oturum ID'si ve iptal zamanı döner; access token ömründen eski kayıtlar dönmez.
+// Bir sonraki istekte cevaptaki "until" değeri since olarak gönderilir.
+// sessions:revoked scope'lu servis token'ı (client_credentials) gerekir; kullanıcı token'ları kabul edilmez.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param since query string false "RFC3339 zaman; bu andan sonra iptal edilenler"
+// @Success 200 {object} domain.RevokedSessionFeed
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /auth/sessions/revoked [get]
+func (h *SessionHandler) Revoked(c echo.Context) error {
+	since, err := queryTime(c, "since", false)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if since == nil {
+		since = &time.Time{}
+	}
+
+	feed, err := h.service.RevokedSince(c.Request().Context(), *since)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, feed)
+}
+
+func sessionError(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
@@ -2,9 +2,10 @@ package http
 
 import (
 	"authentication-service/internal/domain"
+	"errors"
 	"net/http"
+	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,116 +23,199 @@ func (h *UserHandler) RegisterRoutes(e *echo.Echo) {
 	// Rotalar main.go'da group ile yönetiliyor
 }
 
-// GetAll godoc
-// @Summary Tüm Kullanıcıları Getir
-// @Description Veritabanındaki tüm kullanıcıları adresleriyle birlikte listeler.
-// Admin kullanıcılar aktif/pasif tüm kullanıcıları görebilir.
-// Normal kullanıcılar sadece aktif kullanıcıları görebilir.
+// List godoc
+// @Summary Kullanıcıları Listele
+// @Description Kullanıcıları sayfalı olarak listeler. Filtreler veritabanında uygulanır.
+// "users:read" yetkisi gerekir; pasif ve silinmiş kullanıcılar da listelenir.
+// Sadece isteği yapanın organizasyonundaki kullanıcılar listelenir.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} domain.User
+// @Param page query int false "Sayfa (1'den başlar)"
+// @Param limit query int false "Sayfa boyutu (varsayılan 20, en fazla 100)"
+// @Param role query string false "Rol"
+// @Param active query bool false "Aktiflik durumu"
+// @Param deleted query bool false "Sadece silinmiş kullanıcılar"
+// @Param city query string false "Adres şehri"
+// @Param district query string false "Adres ilçesi"
+// @Param created_from query string false "Kayıt tarihi başlangıcı (RFC3339 veya YYYY-MM-DD)"
+// @Param created_to query string false "Kayıt tarihi bitişi (RFC3339 veya YYYY-MM-DD)"
+// @Param q query string false "Ad, soyad veya e-postada arama"
+// @Param sort query string false "Sıralama alanı: created_at, updated_at, email, first_name, last_name"
+// @Param order query string false "asc veya desc"
+// @Param org query string false "Organizasyon (sadece süper admin; boşsa tümü)"
+// @Success 200 {object} domain.UserPage
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /users [get]
-func (h *UserHandler) GetAll(c echo.Context) error {
-	// JWT token'dan kullanıcı bilgilerini al
-	token := c.Get("user")
-	var userRole string = "user" // Default
-
-	if token != nil {
-		if jwtToken, ok := token.(*jwt.Token); ok {
-			if claims, ok := jwtToken.Claims.(jwt.MapClaims); ok {
-				if role, ok := claims["role"].(string); ok {
-					userRole = role
-				}
-			}
-		}
+func (h *UserHandler) List(c echo.Context) error {
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	users, err := h.service.GetAll(c.Request().Context())
+	page, err := h.service.List(c.Request().Context(), filter)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
+	return c.JSON(http.StatusOK, page)
+}
 
-	// Normal user'lar sadece aktif kullanıcıları görebilir
-	if userRole != "admin" {
-		var activeUsers []*domain.User
-		for _, user := range users {
-			if user.Active && user.DeletedAt == nil {
-				activeUsers = append(activeUsers, user)
-			}
-		}
-		users = activeUsers
+func parseUserFilter(c echo.Context) (domain.UserFilter, error) {
+	filter := domain.UserFilter{
+		Role:     c.QueryParam("role"),
+		City:     c.QueryParam("city"),
+		District: c.QueryParam("district"),
+		Search:   c.QueryParam("q"),
+		Sort:     c.QueryParam("sort"),
+		Desc:     strings.EqualFold(c.QueryParam("order"), "desc"),
+		OrgID:    scopedOrganization(c),
+	}
+
+	var err error
+	if filter.Page, err = queryInt(c, "page"); err != nil {
+		return filter, err
 	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.Active, err = queryBool(c, "active"); err != nil {
+		return filter, err
+	}
+	if filter.Deleted, err = queryBool(c, "deleted"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = queryTime(c, "created_from", false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = queryTime(c, "created_to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
 
-	return c.JSON(http.StatusOK, users)
+// UpdateProfileRequest kullanıcının kendi değiştirebildiği alanlar
+type UpdateProfileRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
 }
 
-// GetByID godoc
-// @Summary ID ile Kullanıcı Getir
-// @Description Verilen ID'ye sahip kullanıcıyı ve adreslerini döner.
+// Me godoc
+// @Summary Profilim
+// @Description Token sahibinin kullanıcı bilgilerini ve adreslerini döner.
 // @Tags Users
-// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "User ID"
 // @Success 200 {object} domain.User
 // @Failure 404 {object} map[string]string
-// @Router /users/{id} [get]
-func (h *UserHandler) GetByID(c echo.Context) error {
-	id := c.Param("id")
-	user, err := h.service.Get(c.Request().Context(), id)
+// @Router /users/me [get]
+func (h *UserHandler) Me(c echo.Context) error {
+	user, err := h.service.Get(c.Request().Context(), claimString(c, "user_id"))
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
 	}
 	return c.JSON(http.StatusOK, user)
 }
 
-// Create godoc
-// @Summary Admin Tarafından Kullanıcı Ekle
-// @Description Doğrudan bir user objesi oluşturur. Adres listesi zorunludur.
+// UpdateMe godoc
+// @Summary Profilimi Güncelle
+// @Description Ad ve soyadı günceller. Rol, aktiflik, e-posta ve şifre bu uçtan değiştirilemez;
+// adresler /users/{id}/addresses uçlarından yönetilir.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body domain.User true "User Object (With Addresses)"
-// @Success 201 {object} domain.User
+// @Param request body UpdateProfileRequest true "Profil"
+// @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
-// @Router /users [post]
-func (h *UserHandler) Create(c echo.Context) error {
-	var user domain.User
-	if err := c.Bind(&user); err != nil {
+// @Failure 404 {object} map[string]string
+// @Router /users/me [put]
+func (h *UserHandler) UpdateMe(c echo.Context) error {
+	return h.updateProfile(c, claimString(c, "user_id"))
+}
+
+func (h *UserHandler) updateProfile(c echo.Context, id string) error {
+	var req UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 	}
 
-	if err := h.service.Create(c.Request().Context(), &user); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	profile := &domain.User{FirstName: req.FirstName, LastName: req.LastName}
+	if err := h.service.UpdateProfile(c.Request().Context(), id, profile); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, user)
+	return c.JSON(http.StatusOK, map[string]string{"message": "profile updated"})
+}
+
+// GetByID godoc
+// @Summary ID ile Kullanıcı Getir
+// @Description Verilen ID'ye sahip kullanıcıyı ve adreslerini döner. Kullanıcı sadece kendi kaydını, users:read yetkisi olanlar herkesinkini görebilir.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} domain.User
+// @Failure 404 {object} map[string]string
+// @Router /users/{id} [get]
+func (h *UserHandler) GetByID(c echo.Context) error {
+	id := c.Param("id")
+	user, err := h.service.Get(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
+	}
+	return c.JSON(http.StatusOK, user)
 }
 
 // Update godoc
 // @Summary Kullanıcı Güncelle
-// @Description Mevcut bir kullanıcıyı günceller.
+// @Description Mevcut bir kullanıcıyı günceller; sadece gönderilen alanlar değişir. users:update yetkisi olmayan kullanıcı
+// sadece kendi profil alanlarını (ad, soyad) değiştirebilir; rol değişikliği ayrıca users:manage_roles ister.
+// E-posta değişirse yeni adrese doğrulama linki gönderilir. Adresler ve şifre bu uçtan değişmez.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
-// @Param request body domain.User true "Updated User Object"
+// @Param request body domain.UserUpdate true "Değişecek alanlar"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /users/{id} [put]
 func (h *UserHandler) Update(c echo.Context) error {
 	id := c.Param("id")
-	var user domain.User
-	if err := c.Bind(&user); err != nil {
+	permissions := claimStrings(c, "permissions")
+	if !domain.HasPermission(permissions, domain.PermUsersUpdate) {
+		// Route SelfOrPermission ile korunur; buraya gelen yetkisiz istek kullanıcının kendi kaydıdır
+		return h.updateProfile(c, id)
+	}
+
+	var update domain.UserUpdate
+	if err := c.Bind(&update); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payload"})
 	}
+	if !domain.HasPermission(permissions, domain.PermUsersManageRoles) {
+		update.Role = nil // Rol değişmez
+	}
+	if update.Role != nil && !canAssignRole(c, *update.Role) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": domain.ErrSuperAdminRole.Error()})
+	}
 
-	if err := h.service.Update(c.Request().Context(), id, &user); err != nil {
+	if err := h.service.Update(c.Request().Context(), id, update); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailInUse):
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrRoleNotFound), errors.Is(err, domain.ErrEmailRequired):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
@@ -158,16 +242,18 @@ func (h *UserHandler) Delete(c echo.Context) error {
 
 // ChangeRole godoc
 // @Summary Kullanıcı Rolü Değiştir
-// @Description Admin tarafından kullanıcının rolü değiştirilebilir.
+// @Description Admin tarafından kullanıcının rolü değiştirilebilir. Rol /admin/roles altında tanımlı olmalıdır.
+// Yeni yetkiler kullanıcının bir sonraki login veya token yenilemesinde geçerli olur.
 // @Tags Admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID"
-// @Param request body map[string]string true "Role Request {\"role\": \"admin\" or \"user\"}"
+// @Param request body map[string]string true "Role Request {\"role\": \"collector\"}"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Router /admin/users/{id}/role [put]
 func (h *UserHandler) ChangeRole(c echo.Context) error {
 	id := c.Param("id")
@@ -177,21 +263,42 @@ func (h *UserHandler) ChangeRole(c echo.Context) error {
 	}
 
 	role, ok := req["role"]
-	if !ok || (role != "admin" && role != "user") {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid role. must be 'admin' or 'user'"})
+	if !ok || role == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "role is required"})
 	}
-
-	// UserService'den GetAndChangeRole veya UpdateRole metodunu kullan
-	// Şimdilik sadece Update çağıracağız ve service'de role'ü değiştireceğiz
-	user, err := h.service.Get(c.Request().Context(), id)
-	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{"error": "user not found"})
+	if !canAssignRole(c, role) {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": domain.ErrSuperAdminRole.Error()})
 	}
 
-	user.Role = role
-	if err := h.service.Update(c.Request().Context(), id, user); err != nil {
+	if err := h.service.ChangeRole(c.Request().Context(), id, role); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRoleNotFound):
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid role. see /admin/roles for defined roles"})
+		case errors.Is(err, domain.ErrSuperAdminRole):
+			return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		case errors.Is(err, domain.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "role updated"})
 }
+
+// Unlock godoc
+// @Summary Kullanıcı Kilidini Aç
+// @Description Çok fazla hatalı giriş denemesi nedeniyle kilitlenen hesabın kilidini açar.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/users/{id}/unlock [post]
+func (h *UserHandler) Unlock(c echo.Context) error {
+	id := c.Param("id")
+	if err := h.service.Unlock(c.Request().Context(), id, claimString(c, "user_id")); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "user unlocked"})
+}
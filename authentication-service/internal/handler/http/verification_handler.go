@@ -0,0 +1,76 @@
+package http
+
+import (
+	"authentication-service/internal/domain"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type VerificationHandler struct {
+	service domain.VerificationService
+}
+
+func NewVerificationHandler(service domain.VerificationService) *VerificationHandler {
+	return &VerificationHandler{
+		service: service,
+	}
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+func (h *VerificationHandler) RegisterRoutes(e *echo.Echo) {
+	e.GET("/auth/verify", h.Verify)
+	e.POST("/auth/verify/resend", h.Resend)
+}
+
+// Verify godoc
+// @Summary E-posta Doğrula
+// @Description Kayıt sonrası gönderilen imzalı link ile e-posta adresini doğrular.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Doğrulama Token'ı"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify [get]
+func (h *VerificationHandler) Verify(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+
+	if err := h.service.Verify(c.Request().Context(), token); err != nil {
+		if errors.Is(err, domain.ErrInvalidOneTimeToken) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "email verified"})
+}
+
+// Resend godoc
+// @Summary Doğrulama Linkini Tekrar Gönder
+// @Description Hesap doğrulanmamışsa yeni bir doğrulama linki gönderir. Hesabın varlığını sızdırmamak için her durumda aynı cevap döner.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ResendVerificationRequest true "E-posta"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Router /auth/verify/resend [post]
+func (h *VerificationHandler) Resend(c echo.Context) error {
+	var req ResendVerificationRequest
+	if err := c.Bind(&req); err != nil || req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "email is required"})
+	}
+
+	if err := h.service.Resend(c.Request().Context(), req.Email); err != nil {
+		c.Logger().Error("resend verification: ", err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "if the account needs verification, a new link has been sent"})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey kişisel API anahtarının gönderildiği başlık
+const HeaderAPIKey = "X-API-Key"
+
+// apiKeyClaims anahtar kimliğini handler'ların okuduğu access token claim'lerine çevirir.
+// Oturum ("sid") yoktur; anahtar iptali her istekte veritabanından kontrol edilir.
+func apiKeyClaims(identity *domain.APIKeyIdentity) jwt.MapClaims {
+	// Token'dan okunan claim'ler gibi liste []interface{} olmalı
+	permissions := make([]interface{}, 0, len(identity.Permissions))
+	for _, p := range identity.Permissions {
+		permissions = append(permissions, p)
+	}
+	return jwt.MapClaims{
+		"api_key":     identity.KeyID,
+		"user_id":     identity.UserID,
+		"email":       identity.Email,
+		"role":        identity.Role,
+		"org":         identity.OrgID,
+		"permissions": permissions,
+		"exp":         identity.ExpiresAt.Unix(),
+	}
+}
+
+// DenyAPIKey hesabın kimlik bilgilerini (şifre, MFA, oturumlar, API anahtarları) değiştiren uçları
+// API anahtarlarına kapatır; ele geçirilen bir anahtar hesabın kendisine dönüştürülemez.
+// JWTMiddleware'den sonra kullanılmalıdır.
+func DenyAPIKey() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			if keyID, _ := claims["api_key"].(string); keyID != "" {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": domain.ErrAPIKeyNotAllowed.Error()})
+			}
+
+			return next(c)
+		}
+	}
+}
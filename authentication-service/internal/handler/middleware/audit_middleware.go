@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AuditContext istemcinin IP ve user agent bilgisini request context'ine koyar.
+// Token doğrulanan rotalarda JWTMiddleware aktörü (kullanıcı) ekler.
+func AuditContext() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := domain.WithAuditActor(c.Request().Context(), domain.AuditActor{
+				IP:        c.RealIP(),
+				UserAgent: c.Request().UserAgent(),
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
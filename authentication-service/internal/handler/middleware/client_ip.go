@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor c.RealIP()'nin istemci adresini nasıl bulacağını belirler. Echo'nun varsayılanı
+// X-Forwarded-For/X-Real-IP başlıklarına koşulsuz güvenir; bu başlıklar değiştirilerek IP başına
+// login kilidi aşılabilir ve audit kayıtlarındaki IP sahtelenebilir. Güvenilen proxy yoksa
+// bağlantının adresi kullanılır, varsa X-Forwarded-For sadece bu ağlardan gelen hop'lar için okunur.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
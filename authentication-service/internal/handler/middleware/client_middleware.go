@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ClientTokenVerifier client_credentials ile alınmış servis token'larını doğrular
+type ClientTokenVerifier interface {
+	VerifyClientToken(ctx context.Context, token, scope string) (*domain.OAuthClient, error)
+}
+
+// RequireClientScope servisler arası uçları servis token'ı ve scope ile korur.
+// Kullanıcı token'ları (kullanıcının yetkisi ne olursa olsun) kabul edilmez.
+func RequireClientScope(verifier ClientTokenVerifier, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing client token"})
+			}
+
+			client, err := verifier.VerifyClientToken(c.Request().Context(), authHeader[7:], scope)
+			if errors.Is(err, domain.ErrInsufficientScope) {
+				c.Response().Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+				return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+			}
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+			}
+
+			c.Set("client", client)
+			return next(c)
+		}
+	}
+}
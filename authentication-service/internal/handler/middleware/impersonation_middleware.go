@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// impersonator token'daki "act" claim'inden kullanıcının yerine geçen kişiyi okur
+func impersonator(claims jwt.MapClaims) (id, email string) {
+	act, _ := claims["act"].(map[string]interface{})
+	id, _ = act["sub"].(string)
+	email, _ = act["email"].(string)
+	return id, email
+}
+
+// DenyImpersonation yönetim uçlarını ve hesabın kimlik bilgilerini değiştiren uçları
+// impersonation token'larına kapatır: destek personeli kullanıcının gözünden bakabilir ama
+// rol değiştiremez, kullanıcı silemez, şifre/MFA/oturum/API anahtarlarına dokunamaz.
+// JWTMiddleware'den sonra kullanılmalıdır.
+func DenyImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			if id, _ := impersonator(claims); id != "" {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": domain.ErrImpersonating.Error()})
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -1,49 +1,109 @@
 package middleware
 
 import (
-    "net/http"
-    "strings"
+	"authentication-service/internal/domain"
+	"context"
+	"net/http"
+	"slices"
+	"strings"
 
-    "github.com/golang-jwt/jwt/v5" // v5 kullandığını varsayıyorum
-    "github.com/labstack/echo/v4"
+	"github.com/golang-jwt/jwt/v5" // v5 kullandığını varsayıyorum
+	"github.com/labstack/echo/v4"
 )
 
-func JWTMiddleware(secretKey string) echo.MiddlewareFunc {
-    return func(next echo.HandlerFunc) echo.HandlerFunc {
-        return func(c echo.Context) error {
-            // 1. Header'dan Authorization bilgisini al
-            authHeader := c.Request().Header.Get("Authorization")
-            if authHeader == "" {
-                return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing authorization header"})
-            }
-
-            // 2. Format kontrolü: "Bearer " ile mi başlıyor?
-            // "Bearer " (boşluklu) 7 karakterdir.
-            if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
-                return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid authorization header format"})
-            }
-
-            // 3. "Bearer " kısmını at, sadece token'ı al
-            tokenString := authHeader[7:]
-
-            // 4. Token'ı parse et ve imzayı doğrula
-            token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-                // Algoritma kontrolü (Önemli güvenlik adımı)
-                if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-                    return nil, echo.ErrUnauthorized
-                }
-                return []byte(secretKey), nil
-            })
-
-            if err != nil || !token.Valid {
-                return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
-            }
-
-            // 5. KRİTİK NOKTA: Token'ı context'e "user" anahtarıyla kaydet!
-            // Handler tarafında c.Get("user") dediğinde buraya erişirsin.
-            c.Set("user", token)
-
-            return next(c)
-        }
-    }
-}
\ No newline at end of file
+// SessionChecker token'daki "sid" oturumunun hala açık olup olmadığını söyler
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// APIKeyAuthenticator X-API-Key başlığıyla gelen kişisel API anahtarını doğrular
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*domain.APIKeyIdentity, error)
+}
+
+// JWTMiddleware Bearer access token'ı ya da Authorization yoksa X-API-Key başlığını kabul eder.
+// API anahtarları handler'lara access token ile aynı claim'ler olarak sunulur ("api_key" claim'i ile işaretli).
+func JWTMiddleware(keys domain.KeyService, sessions SessionChecker, apiKeys APIKeyAuthenticator) echo.MiddlewareFunc {
+	return jwtMiddleware(keys, sessions, apiKeys, "access")
+}
+
+// DelegatedJWTMiddleware üçüncü taraf OIDC istemcilerine verilen ("delegated") token'ları da kabul eder.
+// Bu token'lar rol ve yetki taşımaz; sadece /oauth/userinfo gibi kullanıcının kendi bilgisini dönen uçlarda kullanılır.
+func DelegatedJWTMiddleware(keys domain.KeyService, sessions SessionChecker) echo.MiddlewareFunc {
+	return jwtMiddleware(keys, sessions, nil, "access", "delegated")
+}
+
+func jwtMiddleware(keys domain.KeyService, sessions SessionChecker, apiKeys APIKeyAuthenticator, tokenTypes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// 1. Header'dan Authorization bilgisini al
+			authHeader := c.Request().Header.Get("Authorization")
+			if apiKey := c.Request().Header.Get(HeaderAPIKey); authHeader == "" && apiKey != "" && apiKeys != nil {
+				identity, err := apiKeys.Authenticate(c.Request().Context(), apiKey)
+				if err != nil {
+					return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid api key"})
+				}
+				return authenticated(c, next, &jwt.Token{Claims: apiKeyClaims(identity), Valid: true})
+			}
+			if authHeader == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing authorization header"})
+			}
+
+			// 2. Format kontrolü: "Bearer " ile mi başlıyor?
+			// "Bearer " (boşluklu) 7 karakterdir.
+			if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid authorization header format"})
+			}
+
+			// 3. "Bearer " kısmını at, sadece token'ı al
+			tokenString := authHeader[7:]
+
+			// 4. Token'ı parse et ve imzayı "kid"e karşılık gelen public key ile doğrula
+			// Algoritma kontrolü (Önemli güvenlik adımı): sadece asimetrik algoritmalar kabul edilir
+			token, err := jwt.Parse(tokenString, keys.Keyfunc, jwt.WithValidMethods(keys.ValidMethods()))
+
+			if err != nil || !token.Valid {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+			}
+
+			// 5. Oturum kontrolü: logout veya token hırsızlığı sonrası iptal edilen oturumlar reddedilir
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token claims"})
+			}
+			// Doğrulama linki gibi aynı anahtarla imzalanan diğer token'lar access token yerine geçemez
+			if typ, _ := claims["typ"].(string); !slices.Contains(tokenTypes, typ) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid token type"})
+			}
+			sessionID, _ := claims["sid"].(string)
+			if sessionID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token has no session"})
+			}
+			active, err := sessions.IsSessionActive(c.Request().Context(), sessionID)
+			if err != nil || !active {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "session revoked"})
+			}
+
+			return authenticated(c, next, token)
+		}
+	}
+}
+
+// authenticated doğrulanan kimliği context'e koyar ve isteği handler'a geçirir
+func authenticated(c echo.Context, next echo.HandlerFunc, token *jwt.Token) error {
+	// 6. KRİTİK NOKTA: Token'ı context'e "user" anahtarıyla kaydet!
+	// Handler tarafında c.Get("user") dediğinde buraya erişirsin.
+	c.Set("user", token)
+
+	// Audit kayıtları işlemi yapanı request context'inden okur
+	claims, _ := token.Claims.(jwt.MapClaims)
+	actor := domain.AuditActorFromContext(c.Request().Context())
+	actor.UserID, _ = claims["user_id"].(string)
+	actor.Email, _ = claims["email"].(string)
+	org, _ := claims["org"].(string)
+	actor.OrgID = domain.UserOrganization(org)
+	actor.ImpersonatorID, actor.ImpersonatorEmail = impersonator(claims)
+	c.SetRequest(c.Request().WithContext(domain.WithAuditActor(c.Request().Context(), actor)))
+
+	return next(c)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// UserLookup hedef kullanıcının organizasyonunu bulmak için kullanılır
+type UserLookup interface {
+	Get(ctx context.Context, id string) (*domain.User, error)
+}
+
+// RequireSuperAdmin organizasyonlar arası işlemleri (organizasyon, rol ve saklama yönetimi)
+// sadece süper admin'lere açar. JWTMiddleware'den sonra kullanılmalıdır.
+func RequireSuperAdmin() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			role, _ := claims["role"].(string)
+			if !domain.IsSuperAdmin(role) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "super admin required"})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// SameOrganization ":param" ile gelen kullanıcı isteği yapanla aynı organizasyonda değilse
+// 404 döner; böylece başka belediyelerin kullanıcılarının varlığı da sızmaz. Süper admin'ler
+// ve kullanıcının kendi kaydı kontrol edilmez. Organizasyon admin'leri süper admin hesaplarına dokunamaz.
+func SameOrganization(param string, users UserLookup) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			role, _ := claims["role"].(string)
+			userID, _ := claims["user_id"].(string)
+			if domain.IsSuperAdmin(role) || (userID != "" && userID == c.Param(param)) {
+				return next(c)
+			}
+
+			target, err := users.Get(c.Request().Context(), c.Param(param))
+			if err != nil {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": domain.ErrUserNotFound.Error()})
+			}
+			org, _ := claims["org"].(string)
+			if domain.UserOrganization(target.OrgID) != domain.UserOrganization(org) || domain.IsSuperAdmin(target.Role) {
+				return c.JSON(http.StatusNotFound, map[string]string{"error": domain.ErrUserNotFound.Error()})
+			}
+
+			return next(c)
+		}
+	}
+}
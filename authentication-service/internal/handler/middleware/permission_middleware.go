@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"authentication-service/internal/domain"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// RequirePermission token'ın "permissions" claim'inde verilen yetki yoksa 403 döner.
+// JWTMiddleware'den sonra kullanılmalıdır.
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			if !domain.HasPermission(claimPermissions(claims), permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "permission required: " + permission})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// claimPermissions claim'lerdeki yetki listesini okur (JSON'dan []interface{} olarak gelir)
+func claimPermissions(claims jwt.MapClaims) []string {
+	raw, _ := claims["permissions"].([]interface{})
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if s, ok := p.(string); ok {
+			permissions = append(permissions, s)
+		}
+	}
+	return permissions
+}
+
+// SelfOrPermission isteğin kendi kaydına (":param" token'daki user_id ile aynı) yapıldığı
+// veya kullanıcının verilen yetkiye sahip olduğu durumlarda geçişe izin verir.
+func SelfOrPermission(param, permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := c.Get("user").(*jwt.Token)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			}
+
+			claims, ok := token.Claims.(jwt.MapClaims)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid claims"})
+			}
+
+			userID, _ := claims["user_id"].(string)
+			if userID != "" && userID == c.Param(param) {
+				return next(c)
+			}
+			if !domain.HasPermission(claimPermissions(claims), permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "you can only access your own account"})
+			}
+
+			return next(c)
+		}
+	}
+}
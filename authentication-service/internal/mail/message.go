@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"authentication-service/internal/domain"
+	"mime"
+	"strings"
+	"time"
+)
+
+// buildMessage RFC 5322 formatında düz metin mesaj oluşturur
+func buildMessage(from string, msg domain.MailMessage) []byte {
+	// Header injection'a karşı satır sonlarını temizle
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	var b strings.Builder
+	b.WriteString("From: " + header.Replace(from) + "\r\n")
+	b.WriteString("To: " + header.Replace(msg.To) + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", header.Replace(msg.Subject)) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type outboxMailer struct {
+	dir  string
+	from string
+}
+
+// NewOutboxMailer e-postaları göndermek yerine dizine .eml dosyası olarak yazar.
+// Gerçek bir mail sunucusu olmadan geliştirme ve test için kullanılır.
+func NewOutboxMailer(dir, from string) domain.Mailer {
+	return &outboxMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m *outboxMailer) Send(ctx context.Context, msg domain.MailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("outbox dizini oluşturulamadı: %w", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(msg.To)
+	filename := fmt.Sprintf("%d_%s.eml", time.Now().UnixNano(), recipient)
+	return os.WriteFile(filepath.Join(m.dir, filename), buildMessage(m.from, msg), 0o600)
+}
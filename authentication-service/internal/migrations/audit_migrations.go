@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Audit audit_db üzerinde çalışır; waste servisi aynı koleksiyona sadece yazar
+var Audit = []Migration{
+	{
+		Version:     1,
+		Description: "audit_events: time, actor, target and action indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("audit_events"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("created_at")},
+				{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("actor_id_created_at")},
+				{Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("target_created_at")},
+				{Keys: bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("action_created_at")},
+			})
+		},
+	},
+	{
+		Version:     2,
+		Description: "audit_events: per-organization listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("audit_events"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("org_id_created_at")},
+			})
+		},
+	},
+}
@@ -0,0 +1,308 @@
+package migrations
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailCollation e-posta karşılaştırmalarında büyük/küçük harf farkını yok sayar.
+// Unique index bu collation ile oluşturulur; sorguların index'i kullanabilmesi için aynı collation verilmelidir.
+var EmailCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// Auth servisinin migration'ları. Yeni migration'lar listenin sonuna, artan sürümle eklenir.
+var Auth = []Migration{
+	{
+		Version:     1,
+		Description: "users: unique case-insensitive email, deleted_at and role indexes",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := checkDuplicateEmails(ctx, db); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "email", Value: 1}},
+					Options: options.Index().SetName("email_unique").SetUnique(true).SetCollation(EmailCollation),
+				},
+				{Keys: bson.D{{Key: "deleted_at", Value: 1}}, Options: options.Index().SetName("deleted_at")},
+				{Keys: bson.D{{Key: "role", Value: 1}}, Options: options.Index().SetName("role")},
+			})
+		},
+	},
+	{
+		Version:     2,
+		Description: "sessions and refresh_tokens: lookup indexes and refresh token expiry",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			err := createIndexes(ctx, db.Collection("sessions"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+			})
+			if err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection("refresh_tokens"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("token_hash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "session_id", Value: 1}}, Options: options.Index().SetName("session_id")},
+				// Süresi dolan refresh token'lar Mongo tarafından silinir
+				{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0)},
+			})
+		},
+	},
+	{
+		Version:     3,
+		Description: "one_time_tokens: hash lookup, per-user purpose and expiry",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("one_time_tokens"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("token_hash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}}, Options: options.Index().SetName("user_id_purpose")},
+				{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0)},
+			})
+		},
+	},
+	{
+		Version:     4,
+		Description: "login_throttle expiry and lockout_events history index",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			// Kilit ve sayaç pencereleri saatler mertebesinde; bir hafta dokunulmayan kayıtlar artık anlamsız
+			err := createIndexes(ctx, db.Collection("login_throttle"), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "updated_at", Value: 1}},
+					Options: options.Index().SetName("updated_at_ttl").SetExpireAfterSeconds(int32((7 * 24 * time.Hour).Seconds())),
+				},
+			})
+			if err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection("lockout_events"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "email", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("email_created_at")},
+			})
+		},
+	},
+	{
+		Version:     5,
+		Description: "users: created_at index for paginated listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "created_at", Value: -1}}, Options: options.Index().SetName("created_at")},
+			})
+		},
+	},
+	{
+		Version:     6,
+		Description: "users: assign ids to addresses and mark a default address",
+		Up:          backfillAddressIDs,
+	},
+	{
+		Version:     7,
+		Description: "retention_reports: newest-first listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("retention_reports"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "started_at", Value: -1}}, Options: options.Index().SetName("started_at")},
+			})
+		},
+	},
+	{
+		Version:     8,
+		Description: "erasure_requests: newest-first listing and per-user lookup",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("erasure_requests"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "requested_at", Value: -1}}, Options: options.Index().SetName("requested_at")},
+				{Keys: bson.D{{Key: "user_id", Value: 1}}, Options: options.Index().SetName("user_id")},
+			})
+		},
+	},
+	{
+		Version:     9,
+		Description: "sessions: revocation feed lookup",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("sessions"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "revoked_at", Value: 1}}, Options: options.Index().SetName("revoked_at").SetSparse(true)},
+			})
+		},
+	},
+	{
+		Version:     10,
+		Description: "invitations: token lookup, pending-by-email check and listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("invitations"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetName("token_hash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index().SetName("email_status").SetCollation(EmailCollation)},
+				{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("status_created_at")},
+			})
+		},
+	},
+	{
+		Version:     11,
+		Description: "organizations: default organization, org_id backfill and per-organization listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			now := time.Now()
+			_, err := db.Collection("organizations").UpdateOne(ctx,
+				bson.M{"_id": domain.DefaultOrganizationID},
+				bson.M{"$setOnInsert": bson.M{"name": "Varsayılan", "active": true, "created_at": now, "updated_at": now}},
+				options.Update().SetUpsert(true),
+			)
+			if err != nil {
+				return err
+			}
+
+			// Organizasyon öncesi kayıtların hepsi varsayılan organizasyona taşınır
+			for _, name := range []string{"users", "invitations", "erasure_requests"} {
+				_, err := db.Collection(name).UpdateMany(ctx,
+					bson.M{"org_id": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"org_id": domain.DefaultOrganizationID}},
+				)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := createIndexes(ctx, db.Collection("users"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("org_id_created_at")},
+			}); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection("invitations"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("org_id_created_at")},
+			})
+		},
+	},
+	{
+		Version:     12,
+		Description: "oidc: authorization code lookup and expiry, client session revocation",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := createIndexes(ctx, db.Collection("oauth_codes"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "code_hash", Value: 1}}, Options: options.Index().SetName("code_hash_unique").SetUnique(true)},
+				// Kod tekrar kullanımı tespit edilebilsin diye süresi dolan kodlar bir saat daha tutulur
+				{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(int32(time.Hour.Seconds()))},
+			}); err != nil {
+				return err
+			}
+			return createIndexes(ctx, db.Collection("sessions"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "client_id", Value: 1}}, Options: options.Index().SetName("client_id").SetSparse(true)},
+			})
+		},
+	},
+	{
+		Version:     13,
+		Description: "api_keys: prefix lookup and per-user listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("api_keys"), []mongo.IndexModel{
+				{Keys: bson.D{{Key: "prefix", Value: 1}}, Options: options.Index().SetName("prefix_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("user_id_created_at")},
+			})
+		},
+	},
+	{
+		Version:     14,
+		Description: "sessions: impersonation listing",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			return createIndexes(ctx, db.Collection("sessions"), []mongo.IndexModel{
+				{
+					Keys:    bson.D{{Key: "impersonator_id", Value: 1}, {Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("impersonator_id_created_at").SetSparse(true),
+				},
+			})
+		},
+	},
+}
+
+// checkDuplicateEmails büyük/küçük harf farkıyla tekrarlanan e-postaları raporlar. Bu kayıtlar varken
+// unique index E11000 ile oluşturulamaz; hangi hesabın kalacağına otomatik karar verilmez, operatör
+// fazlalıkları birleştirdikten veya e-postalarını değiştirdikten sonra servis yeniden başlatılmalıdır.
+func checkDuplicateEmails(ctx context.Context, db *mongo.Database) error {
+	cursor, err := db.Collection("users").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$email", "ids": bson.M{"$push": "$_id"}, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}, options.Aggregate().SetCollation(EmailCollation))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var duplicates []struct {
+		Email string               `bson:"_id"`
+		IDs   []primitive.ObjectID `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return err
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	conflicts := make([]string, 0, len(duplicates))
+	for _, d := range duplicates {
+		ids := make([]string, 0, len(d.IDs))
+		for _, id := range d.IDs {
+			ids = append(ids, id.Hex())
+		}
+		log.Printf("Duplicate email %q: users %s", d.Email, strings.Join(ids, ", "))
+		conflicts = append(conflicts, fmt.Sprintf("%s [%s]", d.Email, strings.Join(ids, ", ")))
+	}
+	return fmt.Errorf("%d emails differ only by case and must be merged or renamed before the unique index can be built: %s",
+		len(duplicates), strings.Join(conflicts, "; "))
+}
+
+// backfillAddressIDs eski kayıtlardaki ID'siz adreslere ID verir ve varsayılan adresi işaretler.
+// Sadece eksik alanlara dokunduğu için tekrar çalıştırılabilir.
+func backfillAddressIDs(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection("users")
+
+	cursor, err := collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"addresses": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": false}}}},
+		bson.M{"addresses.0": bson.M{"$exists": true}, "addresses.is_default": bson.M{"$ne": true}},
+	}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			Addresses []bson.M           `bson:"addresses"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		hasDefault := false
+		for _, address := range doc.Addresses {
+			if _, ok := address["id"]; !ok {
+				address["id"] = primitive.NewObjectID()
+			}
+			if isDefault, _ := address["is_default"].(bool); isDefault {
+				if hasDefault {
+					address["is_default"] = false
+				}
+				hasDefault = true
+			} else {
+				address["is_default"] = false
+			}
+		}
+		if !hasDefault && len(doc.Addresses) > 0 {
+			doc.Addresses[0]["is_default"] = true
+		}
+
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"addresses": doc.Addresses}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// createIndexes aynı isim ve tanımla var olan index'ler için hata vermez, bu yüzden tekrar çalıştırılabilir
+func createIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, models)
+	return err
+}
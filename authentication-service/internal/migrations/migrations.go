@@ -0,0 +1,87 @@
+// Package migrations MongoDB şemasını (index'ler, veri dönüşümleri) sürümlü olarak günceller.
+// Uygulanan sürümler "schema_migrations" koleksiyonunda tutulur; her migration idempotent
+// yazılmalıdır çünkü birden fazla instance aynı anda başlatılırsa aynı migration iki kez çalışabilir.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "schema_migrations"
+
+// Migration tek bir şema değişikliği. Version bir kez yayınlandıktan sonra değiştirilmemelidir.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Run henüz uygulanmamış migration'ları sürüm sırasıyla çalıştırır.
+// Bir migration hata verirse sonrakiler çalıştırılmaz.
+func Run(ctx context.Context, db *mongo.Database, migrations []Migration) error {
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	pending := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Version < pending[j].Version })
+
+	collection := db.Collection(collectionName)
+	for _, m := range pending {
+		log.Printf("Applying migration %d: %s", m.Version, m.Description)
+		if err := m.Up(ctx, db); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.Version, m.Description, err)
+		}
+
+		// Upsert: aynı anda başka bir instance da kaydetmiş olabilir
+		_, err := collection.UpdateOne(
+			ctx,
+			bson.M{"_id": m.Version},
+			bson.M{"$setOnInsert": appliedMigration{Version: m.Version, Description: m.Description, AppliedAt: time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("recording migration %d: %w", m.Version, err)
+		}
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *mongo.Database) (map[int]bool, error) {
+	cursor, err := db.Collection(collectionName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+	return applied, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoAPIKeyRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoAPIKeyRepository(db *mongo.Database) domain.APIKeyRepository {
+	return &mongoAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (m *mongoAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	collection := m.db.Collection("api_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	key.ID = primitive.NewObjectID()
+	key.CreatedAt = time.Now()
+
+	_, err := collection.InsertOne(ctx, key)
+	return err
+}
+
+func (m *mongoAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return m.findOne(ctx, bson.M{"_id": objectId})
+}
+
+func (m *mongoAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	return m.findOne(ctx, bson.M{"prefix": prefix})
+}
+
+func (m *mongoAPIKeyRepository) findOne(ctx context.Context, filter bson.M) (*domain.APIKey, error) {
+	collection := m.db.Collection("api_keys")
+	var key domain.APIKey
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, filter).Decode(&key)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (m *mongoAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	collection := m.db.Collection("api_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []*domain.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (m *mongoAPIKeyRepository) Revoke(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("api_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoAPIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID) error {
+	collection := m.db.Collection("api_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": time.Now()}})
+	return err
+}
+
+func (m *mongoAPIKeyRepository) DeleteByUser(ctx context.Context, userID string) error {
+	collection := m.db.Collection("api_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
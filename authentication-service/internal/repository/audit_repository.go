@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoAuditRepository struct {
+	db *mongo.Database
+}
+
+// NewMongoAuditRepository audit_db veritabanını alır; koleksiyon waste servisiyle ortaktır
+func NewMongoAuditRepository(db *mongo.Database) domain.AuditRepository {
+	return &mongoAuditRepository{
+		db: db,
+	}
+}
+
+func (m *mongoAuditRepository) Append(ctx context.Context, event *domain.AuditEvent) error {
+	collection := m.db.Collection("audit_events")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	event.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, event)
+	return err
+}
+
+func (m *mongoAuditRepository) List(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, int64, error) {
+	collection := m.db.Collection("audit_events")
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	query := auditQuery(filter)
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []*domain.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (m *mongoAuditRepository) Stream(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	collection := m.db.Collection("audit_events")
+
+	// Dışa aktarma büyük olabilir; tek sorgu için süre sınırı geniş tutulur
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := collection.Find(ctx, auditQuery(filter), opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var event domain.AuditEvent
+		if err := cursor.Decode(&event); err != nil {
+			return err
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func auditQuery(filter domain.AuditFilter) bson.M {
+	query := bson.M{}
+	if filter.Service != "" {
+		query["service"] = filter.Service
+	}
+	if filter.OrgID != "" {
+		query["org_id"] = filter.OrgID
+	}
+	if prefix, ok := strings.CutSuffix(filter.Action, "*"); ok {
+		query["action"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	} else if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.ImpersonatorID != "" {
+		query["impersonator_id"] = filter.ImpersonatorID
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+
+	created := bson.M{}
+	if filter.From != nil {
+		created["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		created["$lte"] = *filter.To
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+	return query
+}
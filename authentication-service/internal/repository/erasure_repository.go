@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoErasureRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoErasureRepository(db *mongo.Database) domain.ErasureRepository {
+	return &mongoErasureRepository{
+		db: db,
+	}
+}
+
+func (m *mongoErasureRepository) Create(ctx context.Context, request *domain.ErasureRequest) error {
+	collection := m.db.Collection("erasure_requests")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	request.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, request)
+	return err
+}
+
+func (m *mongoErasureRepository) Update(ctx context.Context, request *domain.ErasureRequest) error {
+	collection := m.db.Collection("erasure_requests")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": request.ID}, request)
+	return err
+}
+
+func (m *mongoErasureRepository) List(ctx context.Context, orgID string, limit int64) ([]*domain.ErasureRequest, error) {
+	collection := m.db.Collection("erasure_requests")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if orgID != "" {
+		filter["org_id"] = orgID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "requested_at", Value: -1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := []*domain.ErasureRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
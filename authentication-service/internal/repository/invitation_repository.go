@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"authentication-service/internal/migrations"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoInvitationRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoInvitationRepository(db *mongo.Database) domain.InvitationRepository {
+	return &mongoInvitationRepository{
+		db: db,
+	}
+}
+
+func (m *mongoInvitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	collection := m.db.Collection("invitations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	invitation.ID = primitive.NewObjectID()
+	invitation.CreatedAt = time.Now()
+
+	_, err := collection.InsertOne(ctx, invitation)
+	return err
+}
+
+func (m *mongoInvitationRepository) GetByID(ctx context.Context, id string) (*domain.Invitation, error) {
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvitationNotFound
+	}
+	return m.findOne(ctx, bson.M{"_id": objectId}, options.FindOne())
+}
+
+func (m *mongoInvitationRepository) GetByTokenHash(ctx context.Context, hash string) (*domain.Invitation, error) {
+	return m.findOne(ctx, bson.M{"token_hash": hash}, options.FindOne())
+}
+
+func (m *mongoInvitationRepository) GetPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+	return m.findOne(ctx, bson.M{
+		"email":      email,
+		"status":     domain.InvitationPending,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}, options.FindOne().SetCollation(migrations.EmailCollation))
+}
+
+func (m *mongoInvitationRepository) findOne(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*domain.Invitation, error) {
+	collection := m.db.Collection("invitations")
+	var invitation domain.Invitation
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, filter, opts).Decode(&invitation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (m *mongoInvitationRepository) List(ctx context.Context, filter domain.InvitationFilter) ([]*domain.Invitation, int64, error) {
+	collection := m.db.Collection("invitations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	query := bson.M{}
+	now := time.Now()
+	switch filter.Status {
+	case "":
+	case domain.InvitationPending:
+		query["status"] = domain.InvitationPending
+		query["expires_at"] = bson.M{"$gt": now}
+	case domain.InvitationExpired:
+		query["status"] = domain.InvitationPending
+		query["expires_at"] = bson.M{"$lte": now}
+	default:
+		query["status"] = filter.Status
+	}
+	if filter.OrgID != "" {
+		query["org_id"] = filter.OrgID
+	}
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []*domain.Invitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, 0, err
+	}
+	return invitations, total, nil
+}
+
+func (m *mongoInvitationRepository) MarkAccepted(ctx context.Context, id primitive.ObjectID, userID string) (bool, error) {
+	collection := m.db.Collection("invitations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// status filtresi sayesinde aynı davet iki kez kabul edilemez, iptal edilen davet kabul edilemez
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationPending},
+		bson.M{"$set": bson.M{"status": domain.InvitationAccepted, "user_id": userID, "accepted_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoInvitationRepository) Revoke(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("invitations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": domain.InvitationPending},
+		bson.M{"$set": bson.M{"status": domain.InvitationRevoked, "revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoInvitationRepository) DeleteByUser(ctx context.Context, userID, email string) error {
+	collection := m.db.Collection("invitations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx,
+		bson.M{"$or": bson.A{bson.M{"user_id": userID}, bson.M{"email": email}}},
+		options.Delete().SetCollation(migrations.EmailCollation),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = collection.UpdateMany(ctx, bson.M{"invited_by": userID}, bson.M{"$set": bson.M{"invited_by": ""}})
+	return err
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoKeyRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoKeyRepository(db *mongo.Database) domain.KeyRepository {
+	return &mongoKeyRepository{
+		db: db,
+	}
+}
+
+func (m *mongoKeyRepository) CreateKey(ctx context.Context, key *domain.SigningKey) error {
+	collection := m.db.Collection("signing_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.InsertOne(ctx, key)
+	return err
+}
+
+func (m *mongoKeyRepository) ListKeys(ctx context.Context) ([]*domain.SigningKey, error) {
+	collection := m.db.Collection("signing_keys")
+	var keys []*domain.SigningKey
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// En yeni anahtar en başta
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (m *mongoKeyRepository) RetireKeysExcept(ctx context.Context, id string, retiredAt, expiresAt time.Time) error {
+	collection := m.db.Collection("signing_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateMany(
+		ctx,
+		bson.M{"_id": bson.M{"$ne": id}, "retired_at": nil},
+		bson.M{"$set": bson.M{
+			"retired_at": retiredAt,
+			"expires_at": expiresAt,
+		}},
+	)
+	return err
+}
+
+func (m *mongoKeyRepository) DeleteExpiredKeys(ctx context.Context, now time.Time) error {
+	collection := m.db.Collection("signing_keys")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lte": now}})
+	return err
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoOAuthClientRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoOAuthClientRepository(db *mongo.Database) domain.OAuthClientRepository {
+	return &mongoOAuthClientRepository{
+		db: db,
+	}
+}
+
+func (m *mongoOAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	collection := m.db.Collection("oauth_clients")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	client.CreatedAt = time.Now()
+	_, err := collection.InsertOne(ctx, client)
+	return err
+}
+
+func (m *mongoOAuthClientRepository) Upsert(ctx context.Context, client *domain.OAuthClient) error {
+	collection := m.db.Collection("oauth_clients")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": client.ID},
+		bson.M{
+			"$set": bson.M{
+				"name":        client.Name,
+				"secret_hash": client.SecretHash,
+				"scopes":      client.Scopes,
+				"public":      false,
+			},
+			"$setOnInsert": bson.M{"redirect_uris": []string{}, "first_party": true, "created_by": client.CreatedBy, "created_at": time.Now()},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (m *mongoOAuthClientRepository) Get(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	collection := m.db.Collection("oauth_clients")
+	var client domain.OAuthClient
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&client)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOAuthClientNotFound
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (m *mongoOAuthClientRepository) List(ctx context.Context) ([]*domain.OAuthClient, error) {
+	collection := m.db.Collection("oauth_clients")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	clients := []*domain.OAuthClient{}
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func (m *mongoOAuthClientRepository) Delete(ctx context.Context, id string) error {
+	collection := m.db.Collection("oauth_clients")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+type mongoAuthorizationCodeRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoAuthorizationCodeRepository(db *mongo.Database) domain.AuthorizationCodeRepository {
+	return &mongoAuthorizationCodeRepository{
+		db: db,
+	}
+}
+
+func (m *mongoAuthorizationCodeRepository) Create(ctx context.Context, code *domain.AuthorizationCode) error {
+	collection := m.db.Collection("oauth_codes")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.InsertOne(ctx, code)
+	if err != nil {
+		return err
+	}
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		code.ID = oid
+	}
+	return nil
+}
+
+func (m *mongoAuthorizationCodeRepository) GetByHash(ctx context.Context, hash string) (*domain.AuthorizationCode, error) {
+	collection := m.db.Collection("oauth_codes")
+	var code domain.AuthorizationCode
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"code_hash": hash}).Decode(&code)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrCodeNotFound
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (m *mongoAuthorizationCodeRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("oauth_codes")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// used_at filtresi sayesinde aynı kod eşzamanlı iki istekte token'a çevrilemez
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoAuthorizationCodeRepository) SetSession(ctx context.Context, id, sessionID primitive.ObjectID) error {
+	collection := m.db.Collection("oauth_codes")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"session_id": sessionID}})
+	return err
+}
+
+func (m *mongoAuthorizationCodeRepository) DeleteByUser(ctx context.Context, userID string) error {
+	collection := m.db.Collection("oauth_codes")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoOrganizationRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoOrganizationRepository(db *mongo.Database) domain.OrganizationRepository {
+	return &mongoOrganizationRepository{
+		db: db,
+	}
+}
+
+func (m *mongoOrganizationRepository) List(ctx context.Context) ([]*domain.Organization, error) {
+	collection := m.db.Collection("organizations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	orgs := []*domain.Organization{}
+	if err := cursor.All(ctx, &orgs); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (m *mongoOrganizationRepository) Get(ctx context.Context, id string) (*domain.Organization, error) {
+	collection := m.db.Collection("organizations")
+	var org domain.Organization
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"_id": id}).Decode(&org)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (m *mongoOrganizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	collection := m.db.Collection("organizations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	org.CreatedAt = time.Now()
+	org.UpdatedAt = org.CreatedAt
+
+	_, err := collection.InsertOne(ctx, org)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrOrganizationExists
+	}
+	return err
+}
+
+func (m *mongoOrganizationRepository) Update(ctx context.Context, org *domain.Organization) error {
+	collection := m.db.Collection("organizations")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	org.UpdatedAt = time.Now()
+	res, err := collection.UpdateOne(ctx, bson.M{"_id": org.ID}, bson.M{"$set": bson.M{
+		"name":       org.Name,
+		"active":     org.Active,
+		"updated_at": org.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrOrganizationNotFound
+	}
+	return nil
+}
@@ -2,14 +2,16 @@ package repository
 
 import (
 	"authentication-service/internal/domain"
+	"authentication-service/internal/migrations"
 	"context"
 	"errors"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepository struct {
@@ -29,8 +31,9 @@ func (m *mongoRepository) GetByEmail(ctx context.Context, email string) (*domain
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	// Unique index ile aynı collation: büyük/küçük harf farkı gözetilmez ve index kullanılır
 	filter := bson.M{"email": email}
-	err := collection.FindOne(ctx, filter).Decode(&user)
+	err := collection.FindOne(ctx, filter, options.FindOne().SetCollation(migrations.EmailCollation)).Decode(&user)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, errors.New("user not found")
@@ -60,24 +63,101 @@ func (m *mongoRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	return &user, nil
 }
 
-func (m *mongoRepository) GetAll(ctx context.Context) ([]*domain.User, error) {
+func (m *mongoRepository) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, int64, error) {
 	collection := m.db.Collection("users")
-	var users []*domain.User
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Sadece deleted_at == nil olan kullanıcıları getir (soft delete)
-	cursor, err := collection.Find(ctx, bson.M{"deleted_at": nil})
+	query := userListQuery(filter)
+
+	total, err := collection.CountDocuments(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	order := 1
+	if filter.Desc {
+		order = -1
+	}
+	// _id ikincil sıralama: aynı değere sahip kayıtlar sayfalar arasında kaymasın
+	opts := options.Find().
+		SetSort(bson.D{{Key: filter.Sort, Value: order}, {Key: "_id", Value: order}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
 	}
 	defer cursor.Close(ctx)
 
+	users := []*domain.User{}
 	if err = cursor.All(ctx, &users); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return users, nil
+	return users, total, nil
+}
+
+// userListQuery filtreyi Mongo sorgusuna çevirir
+func userListQuery(filter domain.UserFilter) bson.M {
+	query := bson.M{}
+
+	if filter.Deleted != nil && *filter.Deleted {
+		query["deleted_at"] = bson.M{"$ne": nil}
+	} else {
+		query["deleted_at"] = nil
+	}
+	if filter.OrgID != "" {
+		query["org_id"] = filter.OrgID
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	if filter.Active != nil {
+		query["active"] = *filter.Active
+	}
+	if filter.City != "" {
+		query["addresses.city"] = exactInsensitive(filter.City)
+	}
+	if filter.District != "" {
+		query["addresses.district"] = exactInsensitive(filter.District)
+	}
+
+	created := bson.M{}
+	if filter.CreatedFrom != nil {
+		created["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		created["$lte"] = *filter.CreatedTo
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+
+	if filter.Search != "" {
+		// Kullanıcı girdisi regex olarak yorumlanmasın
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Search), Options: "i"}
+		query["$or"] = bson.A{
+			bson.M{"first_name": pattern},
+			bson.M{"last_name": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+	return query
+}
+
+func exactInsensitive(value string) primitive.Regex {
+	return primitive.Regex{Pattern: "^" + regexp.QuoteMeta(value) + "$", Options: "i"}
+}
+
+func (m *mongoRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	return collection.CountDocuments(ctx, bson.M{"role": role, "deleted_at": nil})
 }
 
 func (m *mongoRepository) Create(ctx context.Context, user *domain.User) error {
@@ -91,20 +171,41 @@ func (m *mongoRepository) Create(ctx context.Context, user *domain.User) error {
 	user.ID = primitive.NewObjectID()
 
 	_, err := collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		// GetByEmail kontrolünden sonra eşzamanlı bir kayıt aynı e-postayı almış olabilir
+		return domain.ErrEmailInUse
+	}
 	return err
 }
 
-func (m *mongoRepository) Update(ctx context.Context, user *domain.User) error {
+func (m *mongoRepository) Update(ctx context.Context, user *domain.User, fields ...string) error {
 	collection := m.db.Collection("users")
+	if len(fields) == 0 {
+		return errors.New("no fields to update")
+	}
 
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
-	defer cancel()
+	raw, err := bson.Marshal(user)
+	if err != nil {
+		return err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
 
 	user.UpdatedAt = time.Now()
-	filter := bson.M{"_id": user.ID}
-	update := bson.M{"$set": user}
+	set := bson.M{"updated_at": user.UpdatedAt}
+	for _, field := range fields {
+		set[field] = doc[field] // omitempty ile düşen alanlar null yazılır
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
 
-	_, err := collection.UpdateOne(ctx, filter, update)
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": set})
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrEmailInUse
+	}
 	return err
 }
 
@@ -133,59 +234,252 @@ func (m *mongoRepository) Delete(ctx context.Context, id string) error {
 	return err
 }
 
-// SeedUsers creates demo users for testing
-func (m *mongoRepository) SeedUsers(ctx context.Context) error {
+func (m *mongoRepository) Restore(ctx context.Context, id string) (bool, error) {
 	collection := m.db.Collection("users")
 
-	// Drop existing users for development
-	collection.DeleteMany(ctx, bson.M{})
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, errors.New("invalid id")
+	}
 
-	// Hash password for admin user
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
 
-	adminUser := &domain.User{
-		ID:        primitive.NewObjectID(),
-		Email:     "admin@example.com",
-		FirstName: "Admin",
-		LastName:  "User",
-		Password:  string(hashedPassword),
-		Role:      "admin", // Admin rolü
-		Addresses: []domain.Address{
-			{
-				Title:       "Office",
-				City:        "Istanbul",
-				District:    "Kadikoy",
-				FullAddress: "Kanuni Kampüsü, Istanbul",
-			},
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectId, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"active": true, "updated_at": time.Now()},
 		},
-		Active:    true,
-		DeletedAt: nil,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Demo user
-	userUser := &domain.User{
-		ID:        primitive.NewObjectID(),
-		Email:     "user@example.com",
-		FirstName: "Test",
-		LastName:  "User",
-		Password:  string(hashedPassword),
-		Role:      "user", // Normal user rolü
-		Addresses: []domain.Address{
-			{
-				Title:       "Home",
-				City:        "Istanbul",
-				District:    "Besiktas",
-				FullAddress: "Test Address, Istanbul",
-			},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (m *mongoRepository) Purge(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Aktif kullanıcı yanlışlıkla kalıcı silinmesin diye filtre soft delete'i şart koşar
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount == 1, nil
+}
+
+func (m *mongoRepository) ListDeletedBefore(ctx context.Context, cutoff time.Time, limit int64) ([]*domain.User, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: 1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": cutoff}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (m *mongoRepository) ConsumeMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Filtre koşulu sayesinde eşzamanlı iki istekten yalnızca biri adımı tüketebilir
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "$or": bson.A{
+			bson.M{"mfa_last_step": bson.M{"$lt": step}},
+			bson.M{"mfa_last_step": bson.M{"$exists": false}},
+		}},
+		bson.M{"$set": bson.M{"mfa_last_step": step}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoRepository) ConsumeRecoveryCode(ctx context.Context, id primitive.ObjectID, codeHash string) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "mfa_recovery_codes": codeHash},
+		bson.M{
+			"$pull": bson.M{"mfa_recovery_codes": codeHash},
+			"$set":  bson.M{"updated_at": time.Now()},
 		},
-		Active:    true,
-		DeletedAt: nil,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	)
+	if err != nil {
+		return false, err
 	}
+	return res.ModifiedCount == 1, nil
+}
 
-	_, err := collection.InsertMany(ctx, []interface{}{adminUser, userUser})
-	return err
+func (m *mongoRepository) AddAddress(ctx context.Context, userID primitive.ObjectID, address domain.Address) error {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{
+			"$push": bson.M{"addresses": address},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (m *mongoRepository) UpdateAddress(ctx context.Context, userID primitive.ObjectID, address domain.Address) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Pozisyonel operatör ($) filtrede eşleşen adresi günceller; diğer adreslere dokunulmaz
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID, "addresses.id": address.ID},
+		bson.M{"$set": bson.M{
+			"addresses.$.title":        address.Title,
+			"addresses.$.city":         address.City,
+			"addresses.$.district":     address.District,
+			"addresses.$.full_address": address.FullAddress,
+			"addresses.$.latitude":     address.Latitude,
+			"addresses.$.longitude":    address.Longitude,
+			"updated_at":               time.Now(),
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (m *mongoRepository) RemoveAddress(ctx context.Context, userID, addressID primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// "addresses.1" var ise en az iki adres vardır; son adres bu filtre sayesinde atomik olarak korunur
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID, "addresses.id": addressID, "addresses.1": bson.M{"$exists": true}},
+		bson.M{
+			"$pull": bson.M{"addresses": bson.M{"id": addressID}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	if res.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	// Silinen adres varsayılan ise ilk adres varsayılan olur
+	_, err = collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID, "addresses.is_default": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"addresses.0.is_default": true}},
+	)
+	return true, err
+}
+
+func (m *mongoRepository) SetDefaultAddress(ctx context.Context, userID, addressID primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Pipeline update: tüm adreslerin is_default alanı tek işlemde yeniden hesaplanır
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID, "addresses.id": addressID},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"addresses": bson.M{"$map": bson.M{
+					"input": "$addresses",
+					"as":    "a",
+					"in": bson.M{"$mergeObjects": bson.A{
+						"$$a",
+						bson.M{"is_default": bson.M{"$eq": bson.A{"$$a.id", addressID}}},
+					}},
+				}},
+				"updated_at": time.Now(),
+			}}},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount == 1, nil
+}
+
+func (m *mongoRepository) InsertIfMissing(ctx context.Context, user *domain.User) (bool, error) {
+	collection := m.db.Collection("users")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	user.ID = primitive.NewObjectID()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	doc, err := toBsonM(user)
+	if err != nil {
+		return false, err
+	}
+	// E-posta filtreden gelir; aynı alan $setOnInsert içinde tekrar verilmez
+	delete(doc, "email")
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"email": user.Email},
+		bson.M{"$setOnInsert": doc},
+		options.Update().SetUpsert(true).SetCollation(migrations.EmailCollation),
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount == 1, nil
+}
+
+func toBsonM(v interface{}) (bson.M, error) {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	err = bson.Unmarshal(data, &doc)
+	return doc, err
 }
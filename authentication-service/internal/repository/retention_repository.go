@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRetentionReportRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoRetentionReportRepository(db *mongo.Database) domain.RetentionReportRepository {
+	return &mongoRetentionReportRepository{
+		db: db,
+	}
+}
+
+func (m *mongoRetentionReportRepository) Create(ctx context.Context, report *domain.RetentionReport) error {
+	collection := m.db.Collection("retention_reports")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	report.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(ctx, report)
+	return err
+}
+
+func (m *mongoRetentionReportRepository) List(ctx context.Context, limit int64) ([]*domain.RetentionReport, error) {
+	collection := m.db.Collection("retention_reports")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}}).SetLimit(limit)
+	cursor, err := collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reports := []*domain.RetentionReport{}
+	if err := cursor.All(ctx, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
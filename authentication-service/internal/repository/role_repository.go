@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoRoleRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoRoleRepository(db *mongo.Database) domain.RoleRepository {
+	return &mongoRoleRepository{
+		db: db,
+	}
+}
+
+func (m *mongoRoleRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	collection := m.db.Collection("roles")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	roles := []*domain.Role{}
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (m *mongoRoleRepository) Get(ctx context.Context, name string) (*domain.Role, error) {
+	collection := m.db.Collection("roles")
+	var role domain.Role
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"_id": name}).Decode(&role)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (m *mongoRoleRepository) Create(ctx context.Context, role *domain.Role) error {
+	collection := m.db.Collection("roles")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	role.CreatedAt = time.Now()
+	role.UpdatedAt = role.CreatedAt
+
+	_, err := collection.InsertOne(ctx, role)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrRoleExists
+	}
+	return err
+}
+
+func (m *mongoRoleRepository) Update(ctx context.Context, role *domain.Role) error {
+	collection := m.db.Collection("roles")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	role.UpdatedAt = time.Now()
+	res, err := collection.UpdateOne(ctx, bson.M{"_id": role.Name}, bson.M{"$set": bson.M{
+		"description": role.Description,
+		"permissions": role.Permissions,
+		"updated_at":  role.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
+
+func (m *mongoRoleRepository) Delete(ctx context.Context, name string) error {
+	collection := m.db.Collection("roles")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": name})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrRoleNotFound
+	}
+	return nil
+}
+
+func (m *mongoRoleRepository) EnsureDefaults(ctx context.Context, roles []domain.Role) error {
+	collection := m.db.Collection("roles")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	for _, role := range roles {
+		// $setOnInsert: rol zaten varsa (admin yetkilerini değiştirmiş olabilir) olduğu gibi kalır
+		_, err := collection.UpdateOne(
+			ctx,
+			bson.M{"_id": role.Name},
+			bson.M{"$setOnInsert": bson.M{
+				"description": role.Description,
+				"permissions": role.Permissions,
+				"system":      role.System,
+				"created_at":  now,
+				"updated_at":  now,
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
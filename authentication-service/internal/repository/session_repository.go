@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSessionRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoSessionRepository(db *mongo.Database) domain.SessionRepository {
+	return &mongoSessionRepository{
+		db: db,
+	}
+}
+
+func (m *mongoSessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	session.ID = primitive.NewObjectID()
+	session.CreatedAt = time.Now()
+	session.LastSeenAt = session.CreatedAt
+
+	_, err := collection.InsertOne(ctx, session)
+	return err
+}
+
+func (m *mongoSessionRepository) GetSession(ctx context.Context, id string) (*domain.Session, error) {
+	collection := m.db.Collection("sessions")
+	var session domain.Session
+
+	objectId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid id format")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err = collection.FindOne(ctx, bson.M{"_id": objectId}).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (m *mongoSessionRepository) RevokeSession(ctx context.Context, id primitive.ObjectID) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Zaten iptal edilmiş oturumun tarihini ezme
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+func (m *mongoSessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+func (m *mongoSessionRepository) RevokeClientSessions(ctx context.Context, clientID string) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateMany(
+		ctx,
+		bson.M{"client_id": clientID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+func (m *mongoSessionRepository) DeleteUserSessions(ctx context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if _, err := m.db.Collection("refresh_tokens").DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return err
+	}
+	_, err := m.db.Collection("sessions").DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
+
+func (m *mongoSessionRepository) RevokeOtherSessions(ctx context.Context, userID string, keep primitive.ObjectID) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateMany(
+		ctx,
+		bson.M{"user_id": userID, "_id": bson.M{"$ne": keep}, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+func (m *mongoSessionRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	collection := m.db.Collection("refresh_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := collection.InsertOne(ctx, token)
+	return err
+}
+
+func (m *mongoSessionRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	collection := m.db.Collection("refresh_tokens")
+	var token domain.RefreshToken
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (m *mongoSessionRepository) MarkRefreshTokenUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("refresh_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// used_at filtresi sayesinde aynı token'ı eşzamanlı iki istek kullanamaz
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoSessionRepository) ListUserSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"user_id": userID, "revoked_at": nil}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (m *mongoSessionRepository) ListImpersonations(ctx context.Context, filter domain.ImpersonationFilter) ([]*domain.Session, int64, error) {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	query := bson.M{"impersonator_id": bson.M{"$exists": true}}
+	if filter.ImpersonatorID != "" {
+		query["impersonator_id"] = filter.ImpersonatorID
+	}
+	if filter.OrgID != "" {
+		query["org_id"] = filter.OrgID
+	}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.ActiveOnly {
+		query["revoked_at"] = nil
+		query["expires_at"] = bson.M{"$gt": time.Now()}
+	}
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.Limit)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, 0, err
+	}
+	return sessions, total, nil
+}
+
+func (m *mongoSessionRepository) ListRevokedSince(ctx context.Context, since time.Time) ([]*domain.Session, error) {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "revoked_at", Value: 1}}).
+		SetProjection(bson.M{"_id": 1, "user_id": 1, "revoked_at": 1})
+	cursor, err := collection.Find(ctx, bson.M{"revoked_at": bson.M{"$gt": since}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (m *mongoSessionRepository) TouchSession(ctx context.Context, id primitive.ObjectID, client domain.ClientInfo) error {
+	collection := m.db.Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	set := bson.M{"last_seen_at": time.Now()}
+	if client.IP != "" {
+		set["ip"] = client.IP
+	}
+	if client.UserAgent != "" {
+		set["user_agent"] = client.UserAgent
+	}
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoThrottleRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoThrottleRepository(db *mongo.Database) domain.LoginThrottleRepository {
+	return &mongoThrottleRepository{
+		db: db,
+	}
+}
+
+func (m *mongoThrottleRepository) Get(ctx context.Context, key string) (*domain.LoginThrottleRecord, error) {
+	collection := m.db.Collection("login_throttle")
+	var record domain.LoginThrottleRecord
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"_id": key}).Decode(&record)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil // Hiç başarısız deneme yok
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (m *mongoThrottleRepository) RegisterFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginThrottleRecord, error) {
+	collection := m.db.Collection("login_throttle")
+	var record domain.LoginThrottleRecord
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	// Pipeline update: eşzamanlı denemelerde sayaç kaybolmaz
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"failures": bson.M{"$cond": bson.A{
+				bson.M{"$lt": bson.A{bson.M{"$ifNull": bson.A{"$last_failure_at", time.Time{}}}, now.Add(-window)}},
+				1,
+				bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}},
+			}},
+			"last_failure_at": now,
+			"updated_at":      now,
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	err := collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (m *mongoThrottleRepository) SetLockedUntil(ctx context.Context, key string, until time.Time) error {
+	collection := m.db.Collection("login_throttle")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": key},
+		bson.M{"$set": bson.M{"locked_until": until, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+func (m *mongoThrottleRepository) Reset(ctx context.Context, key string) error {
+	collection := m.db.Collection("login_throttle")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+func (m *mongoThrottleRepository) RecordEvent(ctx context.Context, event *domain.LockoutEvent) error {
+	collection := m.db.Collection("lockout_events")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	event.ID = primitive.NewObjectID()
+	event.CreatedAt = time.Now()
+
+	_, err := collection.InsertOne(ctx, event)
+	return err
+}
+
+func (m *mongoThrottleRepository) DeleteEvents(ctx context.Context, email string) error {
+	collection := m.db.Collection("lockout_events")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"email": email})
+	return err
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoOneTimeTokenRepository struct {
+	db *mongo.Database
+}
+
+func NewMongoOneTimeTokenRepository(db *mongo.Database) domain.OneTimeTokenRepository {
+	return &mongoOneTimeTokenRepository{
+		db: db,
+	}
+}
+
+func (m *mongoOneTimeTokenRepository) Create(ctx context.Context, token *domain.OneTimeToken) error {
+	collection := m.db.Collection("one_time_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := collection.InsertOne(ctx, token)
+	return err
+}
+
+func (m *mongoOneTimeTokenRepository) GetByHash(ctx context.Context, purpose, hash string) (*domain.OneTimeToken, error) {
+	collection := m.db.Collection("one_time_tokens")
+	var token domain.OneTimeToken
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := collection.FindOne(ctx, bson.M{"purpose": purpose, "token_hash": hash}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (m *mongoOneTimeTokenRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	collection := m.db.Collection("one_time_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	res, err := collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (m *mongoOneTimeTokenRepository) DeleteUserTokens(ctx context.Context, userID, purpose string) error {
+	collection := m.db.Collection("one_time_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"user_id": userID, "purpose": purpose, "used_at": nil})
+	return err
+}
+
+func (m *mongoOneTimeTokenRepository) DeleteByUser(ctx context.Context, userID string) error {
+	collection := m.db.Collection("one_time_tokens")
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	_, err := collection.DeleteMany(ctx, bson.M{"user_id": userID})
+	return err
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type addressService struct {
+	repo     domain.UserRepository
+	geocoder domain.Geocoder
+}
+
+func NewAddressService(repo domain.UserRepository, geocoder domain.Geocoder) domain.AddressService {
+	return &addressService{
+		repo:     repo,
+		geocoder: geocoder,
+	}
+}
+
+func (s *addressService) List(ctx context.Context, userID string) ([]domain.Address, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Addresses, nil
+}
+
+func (s *addressService) Default(ctx context.Context, userID string) (*domain.Address, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	address := user.DefaultAddress()
+	if address == nil {
+		return nil, domain.ErrAddressNotFound
+	}
+	return address, nil
+}
+
+func (s *addressService) Add(ctx context.Context, userID string, address *domain.Address) error {
+	if err := validateAddress(address); err != nil {
+		return err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	address.ID = primitive.NewObjectID()
+	geocodeAddress(ctx, s.geocoder, address, nil)
+	makeDefault := address.IsDefault || len(user.Addresses) == 0
+	// Varsayılan işareti SetDefaultAddress ile verilir ki diğer adreslerin işareti de kalksın
+	address.IsDefault = false
+
+	if err := s.repo.AddAddress(ctx, user.ID, *address); err != nil {
+		return err
+	}
+	if makeDefault {
+		if _, err := s.repo.SetDefaultAddress(ctx, user.ID, address.ID); err != nil {
+			return err
+		}
+		address.IsDefault = true
+	}
+	return nil
+}
+
+func (s *addressService) Update(ctx context.Context, userID, addressID string, address *domain.Address) error {
+	if err := validateAddress(address); err != nil {
+		return err
+	}
+	uid, aid, err := parseAddressIDs(userID, addressID)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	var previous *domain.Address
+	for i := range user.Addresses {
+		if user.Addresses[i].ID == aid {
+			previous = &user.Addresses[i]
+		}
+	}
+	if previous == nil {
+		return domain.ErrAddressNotFound
+	}
+
+	address.ID = aid
+	geocodeAddress(ctx, s.geocoder, address, previous)
+	found, err := s.repo.UpdateAddress(ctx, uid, *address)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.ErrAddressNotFound
+	}
+	if address.IsDefault {
+		_, err = s.repo.SetDefaultAddress(ctx, uid, aid)
+	}
+	return err
+}
+
+func (s *addressService) Delete(ctx context.Context, userID, addressID string) error {
+	uid, aid, err := parseAddressIDs(userID, addressID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.repo.RemoveAddress(ctx, uid, aid)
+	if err != nil {
+		return err
+	}
+	if removed {
+		return nil
+	}
+
+	// Silinemediyse sebebini ayır: adres yok mu, yoksa son adres mi
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, a := range user.Addresses {
+		if a.ID == aid {
+			return domain.ErrLastAddress
+		}
+	}
+	return domain.ErrAddressNotFound
+}
+
+func (s *addressService) SetDefault(ctx context.Context, userID, addressID string) error {
+	uid, aid, err := parseAddressIDs(userID, addressID)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.repo.SetDefaultAddress(ctx, uid, aid)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domain.ErrAddressNotFound
+	}
+	return nil
+}
+
+func (s *addressService) getUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func parseAddressIDs(userID, addressID string) (primitive.ObjectID, primitive.ObjectID, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return uid, uid, domain.ErrUserNotFound
+	}
+	aid, err := primitive.ObjectIDFromHex(addressID)
+	if err != nil {
+		return uid, aid, domain.ErrAddressNotFound
+	}
+	return uid, aid, nil
+}
+
+func validateAddress(address *domain.Address) error {
+	address.Title = strings.TrimSpace(address.Title)
+	address.City = strings.TrimSpace(address.City)
+	address.District = strings.TrimSpace(address.District)
+	address.FullAddress = strings.TrimSpace(address.FullAddress)
+	if address.City == "" || address.FullAddress == "" {
+		return domain.ErrInvalidAddress
+	}
+	return nil
+}
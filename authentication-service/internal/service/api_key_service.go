@@ -0,0 +1,228 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Her istekte yazmamak için anahtarın son kullanım zamanı en fazla bu sıklıkla güncellenir
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyConfig anahtarların süre ve adet sınırları
+type APIKeyConfig struct {
+	DefaultTTL time.Duration // Süre verilmezse kullanılır
+	MaxTTL     time.Duration
+	MaxPerUser int // Kullanıcı başına iptal edilmemiş anahtar sayısı
+}
+
+type apiKeyService struct {
+	repo  domain.APIKeyRepository
+	users domain.UserRepository
+	roles domain.RoleService
+	orgs  domain.OrganizationService
+	audit domain.AuditLogger
+	cfg   APIKeyConfig
+}
+
+func NewAPIKeyService(repo domain.APIKeyRepository, users domain.UserRepository, roles domain.RoleService, orgs domain.OrganizationService, audit domain.AuditLogger, cfg APIKeyConfig) domain.APIKeyService {
+	return &apiKeyService{
+		repo:  repo,
+		users: users,
+		roles: roles,
+		orgs:  orgs,
+		audit: audit,
+		cfg:   cfg,
+	}
+}
+
+func (s *apiKeyService) Create(ctx context.Context, key *domain.APIKey, ttl time.Duration) (string, error) {
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" || len(key.Name) > 100 {
+		return "", domain.ErrInvalidAPIKeyName
+	}
+	if ttl == 0 {
+		ttl = s.cfg.DefaultTTL
+	}
+	if ttl < 0 || ttl > s.cfg.MaxTTL {
+		return "", domain.ErrInvalidAPIKeyExpiry
+	}
+
+	user, err := s.users.GetByID(ctx, key.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return "", domain.ErrUserNotFound
+	}
+	scopes, err := s.validateScopes(ctx, user, key.Scopes)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := s.repo.ListByUser(ctx, key.UserID)
+	if err != nil {
+		return "", err
+	}
+	if len(existing) >= s.cfg.MaxPerUser {
+		return "", domain.ErrAPIKeyLimit
+	}
+
+	prefix, err := generateKeyPrefix()
+	if err != nil {
+		return "", err
+	}
+	secret, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	key.Prefix = prefix
+	key.SecretHash = hashToken(secret)
+	key.Scopes = scopes
+	key.OrgID = domain.UserOrganization(user.OrgID)
+	key.ExpiresAt = time.Now().Add(ttl)
+	if err := s.repo.Create(ctx, key); err != nil {
+		return "", err
+	}
+
+	_, after := auditDiff(nil, key)
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditAPIKeyCreate,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   key.ID.Hex(),
+		After:      after,
+	})
+	return domain.APIKeyPrefix + prefix + "_" + secret, nil
+}
+
+// validateScopes scope'lar bilinen yetkiler olmalı ve anahtar sahibinin rolünde bulunmalıdır.
+// "*" kabul edilmez: anahtarlar yetkileri açıkça seçilerek oluşturulur.
+func (s *apiKeyService) validateScopes(ctx context.Context, user *domain.User, requested []string) ([]string, error) {
+	permissions, err := s.roles.Permissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string{}
+	for _, scope := range requested {
+		if !slices.Contains(domain.Permissions, scope) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnknownPermission, scope)
+		}
+		if !domain.HasPermission(permissions, scope) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrAPIKeyScope, scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, domain.ErrInvalidAPIKeyScopes
+	}
+	return scopes, nil
+}
+
+func (s *apiKeyService) List(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, userID, id string) error {
+	key, err := s.repo.GetByID(ctx, id)
+	// Başka kullanıcının anahtarının varlığı da belli edilmez
+	if err != nil || key.UserID != userID {
+		return domain.ErrAPIKeyNotFound
+	}
+	revoked, err := s.repo.Revoke(ctx, key.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditAPIKeyRevoke,
+		TargetType: domain.AuditTargetAPIKey,
+		TargetID:   id,
+		Metadata:   map[string]interface{}{"user_id": userID, "name": key.Name, "prefix": key.Prefix},
+	})
+	return nil
+}
+
+func (s *apiKeyService) Authenticate(ctx context.Context, raw string) (*domain.APIKeyIdentity, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(raw, domain.APIKeyPrefix), "_")
+	if !strings.HasPrefix(raw, domain.APIKeyPrefix) || !ok || prefix == "" || secret == "" {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if key.RevokedAt != nil || time.Now().After(key.ExpiresAt) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// Hesap pasife alınır, silinir veya başka organizasyona taşınırsa anahtar da geçersiz olur
+	user, err := s.users.GetByID(ctx, key.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil || domain.UserOrganization(user.OrgID) != key.OrgID {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if _, err := s.orgs.Resolve(ctx, key.OrgID); err != nil {
+		return nil, domain.ErrOrganizationInactive
+	}
+
+	// Rolden düşen yetkiler anahtardan da düşer
+	rolePermissions, err := s.roles.Permissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	permissions := []string{}
+	for _, scope := range key.Scopes {
+		if domain.HasPermission(rolePermissions, scope) {
+			permissions = append(permissions, scope)
+		}
+	}
+
+	role := user.Role
+	if domain.IsSuperAdmin(role) {
+		role = ""
+	}
+
+	if key.LastUsedAt == nil || time.Since(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID); err != nil {
+			log.Printf("API key touch error: %v", err)
+		}
+	}
+
+	return &domain.APIKeyIdentity{
+		KeyID:       key.ID.Hex(),
+		UserID:      key.UserID,
+		Email:       user.Email,
+		Role:        role,
+		OrgID:       key.OrgID,
+		Permissions: permissions,
+		ExpiresAt:   key.ExpiresAt,
+		CreatedAt:   key.CreatedAt,
+	}, nil
+}
+
+// generateKeyPrefix anahtarı veritabanında bulmak için kullanılan, "_" içermeyen kısa kimlik
+func generateKeyPrefix() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
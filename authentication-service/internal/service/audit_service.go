@@ -0,0 +1,163 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"reflect"
+	"time"
+)
+
+type auditService struct {
+	repo domain.AuditRepository
+}
+
+func NewAuditService(repo domain.AuditRepository) domain.AuditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+// Record aktör ve istemci bilgisini context'ten tamamlar. Denetim kaydı yazılamazsa
+// asıl işlem geri alınmaz, hata loglanır.
+func (s *auditService) Record(ctx context.Context, event domain.AuditEvent) {
+	actor := domain.AuditActorFromContext(ctx)
+	if event.ActorID == "" {
+		event.ActorID = actor.UserID
+	}
+	if event.ActorEmail == "" {
+		event.ActorEmail = actor.Email
+	}
+	if event.ImpersonatorID == "" {
+		event.ImpersonatorID = actor.ImpersonatorID
+		event.ImpersonatorEmail = actor.ImpersonatorEmail
+	}
+	if event.OrgID == "" {
+		event.OrgID = actor.OrgID
+	}
+	if event.IP == "" {
+		event.IP = actor.IP
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = actor.UserAgent
+	}
+	event.Service = domain.AuditServiceName
+	event.CreatedAt = time.Now()
+
+	// İstek iptal edilse bile olay kaydedilmeli
+	if err := s.repo.Append(context.WithoutCancel(ctx), &event); err != nil {
+		log.Printf("Audit record error (%s %s/%s): %v", event.Action, event.TargetType, event.TargetID, err)
+	}
+}
+
+func (s *auditService) List(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+
+	events, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuditPage{Items: events, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+var auditCSVHeader = []string{"created_at", "service", "org_id", "action", "actor_id", "actor_email", "target_type", "target_id", "ip", "user_agent", "before", "after", "metadata", "impersonator_id", "impersonator_email"}
+
+func (s *auditService) Export(ctx context.Context, filter domain.AuditFilter, format string, w io.Writer) error {
+	switch format {
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		return s.repo.Stream(ctx, filter, func(event *domain.AuditEvent) error {
+			return enc.Encode(event)
+		})
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(auditCSVHeader); err != nil {
+			return err
+		}
+		err := s.repo.Stream(ctx, filter, func(event *domain.AuditEvent) error {
+			return cw.Write([]string{
+				event.CreatedAt.UTC().Format(time.RFC3339),
+				event.Service,
+				event.OrgID,
+				event.Action,
+				event.ActorID,
+				event.ActorEmail,
+				event.TargetType,
+				event.TargetID,
+				event.IP,
+				event.UserAgent,
+				jsonCell(event.Before),
+				jsonCell(event.After),
+				jsonCell(event.Metadata),
+				event.ImpersonatorID,
+				event.ImpersonatorEmail,
+			})
+		})
+		cw.Flush()
+		if err != nil {
+			return err
+		}
+		return cw.Error()
+	default:
+		return domain.ErrInvalidAuditFormat
+	}
+}
+
+func jsonCell(v map[string]interface{}) string {
+	if len(v) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// auditDiff iki nesnenin JSON görünümünü karşılaştırıp sadece değişen alanları döner.
+// json:"-" alanlar (şifre, MFA secret'ları) bu sayede kayda hiç girmez.
+func auditDiff(before, after interface{}) (map[string]interface{}, map[string]interface{}) {
+	b, a := toAuditMap(before), toAuditMap(after)
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range b {
+		if !reflect.DeepEqual(value, a[key]) {
+			changedBefore[key] = value
+			if v, ok := a[key]; ok {
+				changedAfter[key] = v
+			}
+		}
+	}
+	for key, value := range a {
+		if _, ok := b[key]; !ok {
+			changedAfter[key] = value
+		}
+	}
+	delete(changedBefore, "updated_at")
+	delete(changedAfter, "updated_at")
+	return changedBefore, changedAfter
+}
+
+func toAuditMap(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	if v == nil {
+		return m
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
@@ -3,46 +3,318 @@ package service
 import (
 	"authentication-service/internal/domain"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	tokenTypeAccess       = "access"
+	tokenTypeDelegated    = "delegated" // Üçüncü taraf OIDC istemcisine verilen access token
+	tokenTypeClient       = "client"    // client_credentials ile servis istemcisine verilen token
+	tokenTypeAPIKey       = "api_key"   // Introspection'da kişisel API anahtarları; JWT değildir
+	tokenTypeMFAChallenge = "mfa_challenge"
+
+	// Her istekte yazmamak için son görülme zamanı en fazla bu sıklıkla güncellenir
+	sessionTouchInterval = time.Minute
+)
+
+// AuthConfig authService'in süre ve politika ayarları
+type AuthConfig struct {
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	AllowUnverifiedLogin bool          // E-postası doğrulanmamış kullanıcılar giriş yapabilir mi
+	MFAChallengeTTL      time.Duration // Şifre adımından sonra MFA kodunun girilmesi için süre
+}
+
 type authService struct {
-	repo      domain.UserRepository
-	jwtSecret string
+	repo         domain.UserRepository
+	sessions     domain.SessionRepository
+	keys         domain.KeyService
+	verification domain.VerificationService
+	throttle     domain.LoginThrottle
+	mfa          domain.MFAService
+	roles        domain.RoleService
+	geocoder     domain.Geocoder
+	orgs         domain.OrganizationService
+	audit        domain.AuditLogger
+	cfg          AuthConfig
 }
 
-func NewAuthService(repo domain.UserRepository, secret string) domain.AuthService {
+func NewAuthService(repo domain.UserRepository, sessions domain.SessionRepository, keys domain.KeyService, verification domain.VerificationService, throttle domain.LoginThrottle, mfa domain.MFAService, roles domain.RoleService, geocoder domain.Geocoder, orgs domain.OrganizationService, audit domain.AuditLogger, cfg AuthConfig) domain.AuthService {
 	return &authService{
-		repo:      repo,
-		jwtSecret: secret,
+		repo:         repo,
+		sessions:     sessions,
+		keys:         keys,
+		verification: verification,
+		throttle:     throttle,
+		mfa:          mfa,
+		roles:        roles,
+		geocoder:     geocoder,
+		orgs:         orgs,
+		audit:        audit,
+		cfg:          cfg,
 	}
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, *domain.User, error) {
-	user, err := s.repo.GetByEmail(ctx, email)
+func (s *authService) Login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	result, err := s.login(ctx, email, password, client)
+	var user *domain.User
+	if result != nil {
+		user = result.User
+	}
+	s.recordLogin(ctx, user, email, "pwd", result, err)
+	return result, err
+}
+
+func (s *authService) login(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	user, err := s.authenticate(ctx, email, password, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// MFA açık ya da rol için zorunluysa token yerine kısa ömürlü challenge token döner
+	if user.MFAEnabled || s.mfa.IsRequired(user) {
+		enroll := !user.MFAEnabled
+		mfaToken, err := s.generateMFAChallenge(user, enroll)
+		if err != nil {
+			return nil, errors.New("error generating token")
+		}
+		return &domain.LoginResult{
+			User:                  user,
+			MFARequired:           true,
+			MFAEnrollmentRequired: enroll,
+			MFAToken:              mfaToken,
+		}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, []string{"pwd"}, client)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// Authenticate şifreyi login ile aynı kurallarla doğrular ama oturum açmaz; OIDC yetkilendirme
+// formu kullanır. MFA gerekiyorsa sonuç MFAToken taşır ve AuthenticateMFA ile tamamlanır.
+// MFA kurulumu bu akışta yapılamaz, kullanıcının önce kendi arayüzümüzden giriş yapması gerekir.
+func (s *authService) Authenticate(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	user, err := s.authenticate(ctx, email, password, client)
+	result := &domain.LoginResult{User: user}
+	if err == nil && (user.MFAEnabled || s.mfa.IsRequired(user)) {
+		if !user.MFAEnabled {
+			err = domain.ErrMFAEnrollmentNeeded
+		} else if result.MFAToken, err = s.generateMFAChallenge(user, false); err != nil {
+			err = errors.New("error generating token")
+		} else {
+			result.MFARequired = true
+		}
+	}
+	if err != nil {
+		s.recordLogin(ctx, user, email, "pwd", nil, err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// AuthenticateMFA Authenticate'ten dönen mfa token'ını oturum açmadan doğrular
+func (s *authService) AuthenticateMFA(ctx context.Context, mfaToken, code, recoveryCode string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	result, user, err := s.verifyMFA(ctx, mfaToken, code, recoveryCode, client, false)
+	if err != nil && user != nil {
+		s.recordLogin(ctx, user, user.Email, "otp", nil, err)
+	}
+	return result, err
+}
+
+// StartClientSession OIDC kod değişiminde istemci adına yeni bir oturum açar. Oturumun
+// ClientID ve Scopes alanları access token'a "client_id" ve "scope" olarak yazılır;
+// Delegated oturumların token'ları rol ve yetki taşımaz.
+func (s *authService) StartClientSession(ctx context.Context, session *domain.Session, client domain.ClientInfo) (*domain.LoginResult, error) {
+	user, err := s.repo.GetByID(ctx, session.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	result := &domain.LoginResult{User: user}
+	result.Tokens, err = s.openSession(ctx, user, session, client)
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		result = nil
+	}
+	s.recordLogin(ctx, user, user.Email, "oidc", result, err)
+	return result, err
+}
+
+func (s *authService) StartImpersonation(ctx context.Context, session *domain.Session, client domain.ClientInfo) (*domain.TokenPair, error) {
+	user, err := s.repo.GetByID(ctx, session.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if err := s.checkOrganization(ctx, user); err != nil {
+		return nil, err
+	}
+	permissions, err := s.roles.Permissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
+		return nil, errors.New("error creating session")
+	}
+	accessToken, err := s.generateToken(user, session, permissions)
+	if err != nil {
+		return nil, errors.New("error generating token")
+	}
+	return &domain.TokenPair{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(time.Until(*session.ExpiresAt).Seconds()),
+	}, nil
+}
+
+// authenticate şifre ile girişin ortak adımları: kilit kontrolü, şifre, hesap ve organizasyon durumu
+func (s *authService) authenticate(ctx context.Context, email, password string, client domain.ClientInfo) (*domain.User, error) {
+	// Kilitli hesap/IP için bcrypt karşılaştırması hiç yapılmaz
+	if err := s.throttle.Check(ctx, email, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByEmail(ctx, email)
+	// Silinmiş hesaplar yok sayılır; geri almanın tek yolu /admin/users/{id}/restore
+	if err != nil || user.DeletedAt != nil {
+		// Var olmayan hesaplar için de sayaç işler, aksi halde hesap taraması yapılabilir
+		s.registerFailure(ctx, email, client.IP)
+		return nil, errors.New("invalid credentials")
 	}
 
 	if !user.Active {
-		return "", nil, errors.New("user is not active")
+		return nil, errors.New("user is not active")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		s.registerFailure(ctx, email, client.IP)
+		return nil, errors.New("invalid credentials")
+	}
+
+	if err := s.throttle.RegisterSuccess(ctx, email); err != nil {
+		log.Printf("Login throttle reset error: %v", err)
+	}
+
+	// Şifre doğrulandıktan sonra kontrol ediliyor ki hesabın durumu yabancılara sızmasın
+	if user.VerificationPending && !s.cfg.AllowUnverifiedLogin {
+		return nil, domain.ErrEmailNotVerified
 	}
+	if err := s.checkOrganization(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// LoginMFA login'in ikinci adımı. Kullanıcı kurulum aşamasındaysa gelen kod bekleyen
+// secret'ı aktifleştirir ve kurtarma kodları bu cevapta bir kez döner.
+func (s *authService) LoginMFA(ctx context.Context, mfaToken, code, recoveryCode string, client domain.ClientInfo) (*domain.LoginResult, error) {
+	result, user, err := s.loginMFA(ctx, mfaToken, code, recoveryCode, client)
+	if user != nil {
+		s.recordLogin(ctx, user, user.Email, "otp", result, err)
+	}
+	return result, err
+}
 
-	token, err := s.generateToken(user)
+// recordLogin başarılı girişleri ve hatalı denemeleri kaydeder. MFA adımı bekleyen şifre
+// doğrulaması henüz giriş sayılmaz; oturum ikinci adımda açılınca kaydedilir.
+func (s *authService) recordLogin(ctx context.Context, user *domain.User, email, method string, result *domain.LoginResult, err error) {
+	if err == nil && result.Tokens == nil {
+		return
+	}
+
+	event := domain.AuditEvent{Action: domain.AuditLogin, ActorEmail: email, TargetType: domain.AuditTargetUser, Metadata: map[string]interface{}{"method": method}}
+	if user != nil {
+		event.ActorID = user.ID.Hex()
+		event.TargetID = event.ActorID
+		event.OrgID = domain.UserOrganization(user.OrgID)
+	}
+	if err != nil {
+		event.Action = domain.AuditLoginFailed
+		event.Metadata["reason"] = err.Error()
+	}
+	s.audit.Record(ctx, event)
+}
+
+func (s *authService) loginMFA(ctx context.Context, mfaToken, code, recoveryCode string, client domain.ClientInfo) (*domain.LoginResult, *domain.User, error) {
+	result, user, err := s.verifyMFA(ctx, mfaToken, code, recoveryCode, client, true)
 	if err != nil {
-		return "", nil, errors.New("error generating token")
+		return nil, user, err
 	}
 
-	return token, user, nil
+	tokens, err := s.startSession(ctx, user, []string{"pwd", "otp"}, client)
+	if err != nil {
+		return nil, user, err
+	}
+	result.Tokens = tokens
+	return result, user, nil
+}
+
+// verifyMFA challenge token'ı ve kodu doğrular. allowEnroll false ise kurulum token'ları reddedilir.
+func (s *authService) verifyMFA(ctx context.Context, mfaToken, code, recoveryCode string, client domain.ClientInfo, allowEnroll bool) (*domain.LoginResult, *domain.User, error) {
+	user, enroll, err := s.parseMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	if enroll && !allowEnroll {
+		return nil, nil, domain.ErrInvalidMFAToken
+	}
+
+	// Kod denemeleri de şifre denemeleri gibi sayılır; 6 haneli kod kaba kuvvetle denenemesin
+	if err := s.throttle.Check(ctx, user.Email, client.IP); err != nil {
+		return nil, user, err
+	}
+
+	result := &domain.LoginResult{User: user}
+	if enroll {
+		codes, err := s.mfa.ActivateEnrollment(ctx, user.ID.Hex(), code)
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			s.registerFailure(ctx, user.Email, client.IP)
+		}
+		if err != nil {
+			return nil, user, err
+		}
+		result.RecoveryCodes = codes
+	} else {
+		err := s.mfa.VerifyLogin(ctx, user, code, recoveryCode)
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			s.registerFailure(ctx, user.Email, client.IP)
+		}
+		if err != nil {
+			return nil, user, err
+		}
+	}
+
+	if err := s.throttle.RegisterSuccess(ctx, user.Email); err != nil {
+		log.Printf("Login throttle reset error: %v", err)
+	}
+	return result, user, nil
+}
+
+// BeginMFAEnrollment MFA zorunlu olup henüz kurulum yapmamış kullanıcının
+// token almadan önce secret üretebilmesini sağlar
+func (s *authService) BeginMFAEnrollment(ctx context.Context, mfaToken string) (*domain.MFAEnrollment, error) {
+	user, enroll, err := s.parseMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !enroll {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	return s.mfa.BeginEnrollment(ctx, user.ID.Hex())
 }
 
 func (s *authService) Register(ctx context.Context, user *domain.User) error {
@@ -54,7 +326,13 @@ func (s *authService) Register(ctx context.Context, user *domain.User) error {
 	// 2. Validasyon: Email Kontrolü
 	existing, _ := s.repo.GetByEmail(ctx, user.Email)
 	if existing != nil {
-		return errors.New("email already in use")
+		return domain.ErrEmailInUse
+	}
+
+	// 3. Validasyon: Organizasyon belirtilmediyse varsayılana kaydolunur
+	org, err := s.orgs.Resolve(ctx, domain.UserOrganization(user.OrgID))
+	if err != nil {
+		return err
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
@@ -64,18 +342,253 @@ func (s *authService) Register(ctx context.Context, user *domain.User) error {
 	user.Password = string(hashedPassword)
 	user.Active = true
 	user.Role = "user" // Yeni kullanıcılar default "user" rolü alır
+	user.OrgID = org.ID
+	user.VerificationPending = true
+	user.Addresses = domain.PrepareAddresses(user.Addresses)
+	geocodeAddresses(ctx, s.geocoder, user.Addresses, nil)
+
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditRegister, ActorID: user.ID.Hex(), ActorEmail: user.Email, OrgID: user.OrgID, TargetType: domain.AuditTargetUser, TargetID: user.ID.Hex()})
+
+	// Mail gönderilemese bile kayıt geçerli; kullanıcı /auth/verify/resend ile tekrar isteyebilir
+	if err := s.verification.SendVerification(ctx, user); err != nil {
+		log.Printf("Verification mail error for %s: %v", user.Email, err)
+	}
+	return nil
+}
+
+// Refresh refresh token'ı döndürür (rotation). Daha önce kullanılmış bir token
+// tekrar gelirse çalındığı varsayılır ve tüm aile (oturum) iptal edilir.
+func (s *authService) Refresh(ctx context.Context, refreshToken string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	stored, err := s.sessions.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		_ = s.sessions.RevokeSession(ctx, stored.SessionID)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	session, err := s.sessions.GetSession(ctx, stored.SessionID.Hex())
+	if err != nil || session.RevokedAt != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	marked, err := s.sessions.MarkRefreshTokenUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Aynı token eşzamanlı olarak başka bir istekte kullanıldı
+		_ = s.sessions.RevokeSession(ctx, stored.SessionID)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	user, err := s.repo.GetByID(ctx, stored.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		_ = s.sessions.RevokeSession(ctx, stored.SessionID)
+		return nil, domain.ErrInvalidRefreshToken
+	}
 
-	return s.repo.Create(ctx, user)
+	if err := s.sessions.TouchSession(ctx, session.ID, client); err != nil {
+		log.Printf("Session touch error: %v", err)
+	}
+	return s.issueTokens(ctx, user, session)
+}
+
+// Logout refresh token'ın ait olduğu oturumu kapatır; oturuma bağlı access token'lar da reddedilir
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.sessions.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return domain.ErrInvalidRefreshToken
+	}
+	return s.sessions.RevokeSession(ctx, stored.SessionID)
 }
 
-func (s *authService) generateToken(user *domain.User) (string, error) {
+func (s *authService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if session.RevokedAt != nil {
+		return false, nil
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessions.TouchSession(ctx, session.ID, domain.ClientInfo{}); err != nil {
+			log.Printf("Session touch error: %v", err)
+		}
+	}
+	return true, nil
+}
+
+func (s *authService) registerFailure(ctx context.Context, email, ip string) {
+	if err := s.throttle.RegisterFailure(ctx, email, ip); err != nil {
+		log.Printf("Login throttle error: %v", err)
+	}
+}
+
+// startSession her başarılı login için yeni bir oturum (refresh token ailesi) başlatır
+func (s *authService) startSession(ctx context.Context, user *domain.User, amr []string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	return s.openSession(ctx, user, &domain.Session{UserID: user.ID.Hex(), AMR: amr}, client)
+}
+
+func (s *authService) openSession(ctx context.Context, user *domain.User, session *domain.Session, client domain.ClientInfo) (*domain.TokenPair, error) {
+	session.UserAgent = client.UserAgent
+	session.IP = client.IP
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
+		return nil, errors.New("error creating session")
+	}
+
+	tokens, err := s.issueTokens(ctx, user, session)
+	if errors.Is(err, domain.ErrOrganizationInactive) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.New("error generating token")
+	}
+	return tokens, nil
+}
+
+// checkOrganization pasif ya da silinmiş organizasyonların kullanıcılarına token verilmesini engeller
+func (s *authService) checkOrganization(ctx context.Context, user *domain.User) error {
+	if _, err := s.orgs.Resolve(ctx, domain.UserOrganization(user.OrgID)); err != nil {
+		return domain.ErrOrganizationInactive
+	}
+	return nil
+}
+
+func (s *authService) generateMFAChallenge(user *domain.User, enroll bool) (string, error) {
+	now := time.Now()
+	return s.keys.Sign(jwt.MapClaims{
+		"typ":    tokenTypeMFAChallenge,
+		"sub":    user.ID.Hex(),
+		"enroll": enroll,
+		"iat":    now.Unix(),
+		"exp":    now.Add(s.cfg.MFAChallengeTTL).Unix(),
+	})
+}
+
+func (s *authService) parseMFAChallenge(ctx context.Context, mfaToken string) (*domain.User, bool, error) {
+	parsed, err := jwt.Parse(mfaToken, s.keys.Keyfunc, jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return nil, false, domain.ErrInvalidMFAToken
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != tokenTypeMFAChallenge {
+		return nil, false, domain.ErrInvalidMFAToken
+	}
+	userID, _ := claims["sub"].(string)
+	enroll, _ := claims["enroll"].(bool)
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return nil, false, domain.ErrInvalidMFAToken
+	}
+	// Challenge alındıktan sonra MFA başka bir yoldan açıldıysa kurulum token'ı geçersizdir
+	if enroll && user.MFAEnabled {
+		return nil, false, domain.ErrInvalidMFAToken
+	}
+	if !enroll && !user.MFAEnabled {
+		return nil, false, domain.ErrInvalidMFAToken
+	}
+	return user, enroll, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *domain.User, session *domain.Session) (*domain.TokenPair, error) {
+	// Organizasyon pasife alındıysa refresh ile de yeni token alınamaz
+	if err := s.checkOrganization(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// Yetkiler her token üretiminde rolden okunur; rol değişiklikleri refresh ile yansır
+	permissions, err := s.roles.Permissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.generateToken(user, session, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateRandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.sessions.CreateRefreshToken(ctx, &domain.RefreshToken{
+		SessionID: session.ID,
+		UserID:    user.ID.Hex(),
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.cfg.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (s *authService) generateToken(user *domain.User, session *domain.Session, permissions []string) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"user_id": user.ID.Hex(),
-		"email":   user.Email,
-		"role":    user.Role, // Rol'ü token'a ekle
-		"exp":     time.Now().Add(time.Hour * 72).Unix(),
+		"typ":            tokenTypeAccess,
+		"user_id":        user.ID.Hex(),
+		"email":          user.Email,
+		"email_verified": !user.VerificationPending,
+		"role":           user.Role,                           // Rol'ü token'a ekle
+		"org":            domain.UserOrganization(user.OrgID), // Servisler sorguları bu organizasyona göre kapsar
+		"permissions":    permissions,                         // Servisler yetki kontrolünü rol adı yerine bunlarla yapar
+		"sid":            session.ID.Hex(),                    // Oturum iptali kontrolü için
+		"amr":            session.AMR,                         // Kullanılan doğrulama yöntemleri (pwd, otp)
+		"iat":            now.Unix(),
+		"exp":            now.Add(s.cfg.AccessTokenTTL).Unix(),
+	}
+	// OIDC istemcisine verilen token'lar istemciyi ve onaylanan scope'ları taşır
+	if session.ClientID != "" {
+		claims["client_id"] = session.ClientID
+		claims["scope"] = strings.Join(session.Scopes, " ")
+	}
+	// Üçüncü taraf uygulama kullanıcının yetkileriyle API'lerimizi çağıramaz; token sadece userinfo'da geçer
+	if session.Delegated {
+		claims["typ"] = tokenTypeDelegated
+		delete(claims, "role")
+		delete(claims, "permissions")
+	}
+	// Impersonation token'ı oturumla birlikte biter ve yerine geçen kişiyi taşır (RFC 8693 "act")
+	if session.ImpersonatorID != "" {
+		claims["exp"] = session.ExpiresAt.Unix()
+		claims["act"] = domain.Actor{Sub: session.ImpersonatorID, Email: session.ImpersonatorEmail}
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
+	return s.keys.Sign(claims)
+}
+
+// generateRandomToken URL'de taşınabilir, tahmin edilemez bir token üretir
+func generateRandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken token'ları veritabanında düz metin tutmamak için kullanılır
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
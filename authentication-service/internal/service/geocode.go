@@ -0,0 +1,45 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"log"
+)
+
+// geocodeAddresses koordinatı olmayan adresleri geocode eder. previous güncelleme öncesi adreslerdir.
+func geocodeAddresses(ctx context.Context, geocoder domain.Geocoder, addresses []domain.Address, previous []domain.Address) {
+	for i := range addresses {
+		var old *domain.Address
+		for j := range previous {
+			if !addresses[i].ID.IsZero() && previous[j].ID == addresses[i].ID {
+				old = &previous[j]
+				break
+			}
+		}
+		geocodeAddress(ctx, geocoder, &addresses[i], old)
+	}
+}
+
+// geocodeAddress istemcinin gönderdiği koordinatı korur; il/ilçe değiştiği halde eski koordinat
+// geri yollandıysa koordinat yeniden hesaplanır. Geocode hatası kaydı engellemez, adres koordinatsız kalır.
+func geocodeAddress(ctx context.Context, geocoder domain.Geocoder, address *domain.Address, previous *domain.Address) {
+	if address.HasCoordinates() {
+		stale := previous != nil &&
+			(previous.City != address.City || previous.District != address.District) &&
+			previous.Latitude == address.Latitude && previous.Longitude == address.Longitude
+		if !stale {
+			return
+		}
+	}
+
+	lat, lng, err := geocoder.Geocode(ctx, address.City, address.District)
+	if err != nil {
+		if !errors.Is(err, domain.ErrLocationUnknown) {
+			log.Printf("Geocode error for %s/%s: %v", address.City, address.District, err)
+		}
+		address.Latitude, address.Longitude = 0, 0
+		return
+	}
+	address.Latitude, address.Longitude = lat, lng
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"strings"
+	"time"
+)
+
+type impersonationService struct {
+	users    domain.UserRepository
+	sessions domain.SessionRepository
+	auth     domain.AuthService
+	roles    domain.RoleService
+	audit    domain.AuditLogger
+	ttl      time.Duration
+}
+
+func NewImpersonationService(users domain.UserRepository, sessions domain.SessionRepository, auth domain.AuthService, roles domain.RoleService, audit domain.AuditLogger, ttl time.Duration) domain.ImpersonationService {
+	return &impersonationService{
+		users:    users,
+		sessions: sessions,
+		auth:     auth,
+		roles:    roles,
+		audit:    audit,
+		ttl:      ttl,
+	}
+}
+
+func (s *impersonationService) Start(ctx context.Context, req domain.ImpersonationRequest, client domain.ClientInfo) (*domain.ImpersonationResult, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" || len(req.Reason) > 500 {
+		return nil, domain.ErrImpersonationReason
+	}
+
+	target, err := s.users.GetByID(ctx, req.UserID)
+	if err != nil || !target.Active || target.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	if target.ID.Hex() == req.Actor.Sub || domain.IsSuperAdmin(target.Role) {
+		return nil, domain.ErrImpersonationNotAllowed
+	}
+
+	// Yerine geçilen kullanıcının yetkileri isteği yapanınkileri aşamaz; aksi halde
+	// sadece users:impersonate yetkisi olan biri admin'in yerine geçerek yetki kazanırdı
+	permissions, err := s.roles.Permissions(ctx, target.Role)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if !domain.HasPermission(req.Permissions, permission) {
+			return nil, domain.ErrImpersonationNotAllowed
+		}
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	session := &domain.Session{
+		UserID:            target.ID.Hex(),
+		ImpersonatorID:    req.Actor.Sub,
+		ImpersonatorEmail: req.Actor.Email,
+		Reason:            req.Reason,
+		OrgID:             domain.UserOrganization(target.OrgID),
+		ExpiresAt:         &expiresAt,
+	}
+	tokens, err := s.auth.StartImpersonation(ctx, session, client)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		OrgID:      session.OrgID,
+		Action:     domain.AuditImpersonationStart,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   session.UserID,
+		Metadata:   map[string]interface{}{"session_id": session.ID.Hex(), "reason": session.Reason, "expires_at": expiresAt},
+	})
+
+	return &domain.ImpersonationResult{
+		AccessToken: tokens.AccessToken,
+		ExpiresIn:   tokens.ExpiresIn,
+		ExpiresAt:   expiresAt,
+		SessionID:   session.ID.Hex(),
+		User:        target,
+	}, nil
+}
+
+func (s *impersonationService) List(ctx context.Context, filter domain.ImpersonationFilter) (*domain.ImpersonationPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+
+	sessions, total, err := s.sessions.ListImpersonations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ImpersonationPage{Items: sessions, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (s *impersonationService) End(ctx context.Context, sessionID, orgID string) error {
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	// Başka organizasyonun oturumunun varlığı da belli edilmez
+	if err != nil || session.ImpersonatorID == "" || (orgID != "" && session.OrgID != orgID) {
+		return domain.ErrImpersonationNotFound
+	}
+	if session.RevokedAt != nil || time.Now().After(*session.ExpiresAt) {
+		return domain.ErrImpersonationNotFound
+	}
+	if err := s.sessions.RevokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		OrgID:      session.OrgID,
+		Action:     domain.AuditImpersonationEnd,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   session.UserID,
+		Metadata: map[string]interface{}{
+			"session_id":         sessionID,
+			"impersonator_id":    session.ImpersonatorID,
+			"impersonator_email": session.ImpersonatorEmail,
+		},
+	})
+	return nil
+}
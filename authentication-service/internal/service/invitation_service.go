@@ -0,0 +1,225 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type invitationService struct {
+	repo       domain.InvitationRepository
+	users      domain.UserRepository
+	userSvc    domain.UserService
+	roles      domain.RoleRepository
+	orgs       domain.OrganizationService
+	mailer     domain.Mailer
+	appBaseURL string
+	ttl        time.Duration
+	audit      domain.AuditLogger
+}
+
+func NewInvitationService(repo domain.InvitationRepository, users domain.UserRepository, userSvc domain.UserService, roles domain.RoleRepository, orgs domain.OrganizationService, mailer domain.Mailer, appBaseURL string, ttl time.Duration, audit domain.AuditLogger) domain.InvitationService {
+	return &invitationService{
+		repo:       repo,
+		users:      users,
+		userSvc:    userSvc,
+		roles:      roles,
+		orgs:       orgs,
+		mailer:     mailer,
+		appBaseURL: strings.TrimRight(appBaseURL, "/"),
+		ttl:        ttl,
+		audit:      audit,
+	}
+}
+
+func (s *invitationService) Invite(ctx context.Context, invitation *domain.Invitation) error {
+	invitation.Email = strings.TrimSpace(invitation.Email)
+	if invitation.Email == "" {
+		return errors.New("email is required")
+	}
+	if invitation.Role == "" {
+		invitation.Role = domain.RoleUser
+	}
+	if _, err := s.roles.Get(ctx, invitation.Role); err != nil {
+		return err
+	}
+	org, err := s.orgs.Resolve(ctx, domain.UserOrganization(invitation.OrgID))
+	if err != nil {
+		return err
+	}
+	invitation.OrgID = org.ID
+
+	if existing, _ := s.users.GetByEmail(ctx, invitation.Email); existing != nil {
+		return domain.ErrEmailInUse
+	}
+	if pending, _ := s.repo.GetPendingByEmail(ctx, invitation.Email); pending != nil {
+		return domain.ErrInvitationExists
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return err
+	}
+	invitation.Status = domain.InvitationPending
+	invitation.TokenHash = hashToken(token)
+	invitation.ExpiresAt = time.Now().Add(s.ttl)
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", s.appBaseURL, url.QueryEscape(token))
+	err = s.mailer.Send(ctx, domain.MailMessage{
+		To:      invitation.Email,
+		Subject: "Hesap daveti",
+		Body: fmt.Sprintf("Merhaba %s,\n\nSizin için bir hesap oluşturuldu. Şifrenizi ve adresinizi belirleyerek hesabınızı açmak için aşağıdaki linki kullanın:\n%s\n\n"+
+			"Link %d gün boyunca ve yalnızca bir kez geçerlidir. Bu daveti beklemiyorsanız bu e-postayı dikkate almayın.\n",
+			invitation.FirstName, link, int(s.ttl.Hours()/24)),
+	})
+	if err != nil {
+		// Link ulaşmadıysa davet bekler görünmesin; admin tekrar davet edebilir
+		log.Printf("Invitation mail error: %v", err)
+		if _, revokeErr := s.repo.Revoke(ctx, invitation.ID); revokeErr != nil {
+			log.Printf("Invitation revoke error: %v", revokeErr)
+		}
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditInvitationCreate,
+		TargetType: domain.AuditTargetInvitation,
+		TargetID:   invitation.ID.Hex(),
+		OrgID:      invitation.OrgID,
+		After:      map[string]interface{}{"email": invitation.Email, "role": invitation.Role},
+	})
+	return nil
+}
+
+func (s *invitationService) List(ctx context.Context, filter domain.InvitationFilter) (*domain.InvitationPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+
+	invitations, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, invitation := range invitations {
+		if invitation.Status == domain.InvitationPending && now.After(invitation.ExpiresAt) {
+			invitation.Status = domain.InvitationExpired
+		}
+	}
+	return &domain.InvitationPage{Items: invitations, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
+}
+
+func (s *invitationService) Revoke(ctx context.Context, id, orgID string) error {
+	invitation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Başka organizasyonun daveti yokmuş gibi davranılır
+	if orgID != "" && domain.UserOrganization(invitation.OrgID) != orgID {
+		return domain.ErrInvitationNotFound
+	}
+	revoked, err := s.repo.Revoke(ctx, invitation.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrInvitationClosed
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditInvitationRevoke,
+		TargetType: domain.AuditTargetInvitation,
+		TargetID:   id,
+		OrgID:      invitation.OrgID,
+		Before:     map[string]interface{}{"email": invitation.Email, "role": invitation.Role},
+	})
+	return nil
+}
+
+func (s *invitationService) Preview(ctx context.Context, token string) (*domain.InvitationPreview, error) {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.InvitationPreview{
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		FirstName: invitation.FirstName,
+		LastName:  invitation.LastName,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+func (s *invitationService) Accept(ctx context.Context, token string, acceptance domain.InvitationAcceptance) (*domain.User, error) {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(acceptance.Password) < minPasswordLength {
+		return nil, domain.ErrWeakPassword
+	}
+
+	user := &domain.User{
+		Email:     invitation.Email,
+		Password:  acceptance.Password,
+		FirstName: acceptance.FirstName,
+		LastName:  acceptance.LastName,
+		Role:      invitation.Role,
+		OrgID:     invitation.OrgID,
+		Addresses: acceptance.Addresses,
+	}
+	if user.FirstName == "" {
+		user.FirstName = invitation.FirstName
+	}
+	if user.LastName == "" {
+		user.LastName = invitation.LastName
+	}
+
+	// Hesap oluşturma e-posta, adres ve rol kontrollerini yapar; e-posta tekilliği eşzamanlı kabulü de engeller
+	ctx = domain.WithAuditActor(ctx, domain.AuditActor{Email: invitation.Email, OrgID: invitation.OrgID})
+	if err := s.userSvc.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userID := user.ID.Hex()
+	accepted, err := s.repo.MarkAccepted(ctx, invitation.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		// Hesap oluşturulurken davet iptal edildi; hesap açık kalır, admin isterse siler
+		log.Printf("Invitation %s was closed while user %s was being created", invitation.ID.Hex(), userID)
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditInvitationAccept,
+		ActorID:    userID,
+		TargetType: domain.AuditTargetInvitation,
+		TargetID:   invitation.ID.Hex(),
+		Metadata:   map[string]interface{}{"user_id": userID, "invited_by": invitation.InvitedBy},
+	})
+	return user, nil
+}
+
+// pending token'a ait daveti süresi dolmamış ve bekliyorsa döner
+func (s *invitationService) pending(ctx context.Context, token string) (*domain.Invitation, error) {
+	invitation, err := s.repo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil || invitation.Status != domain.InvitationPending || time.Now().After(invitation.ExpiresAt) {
+		return nil, domain.ErrInvalidInvitation
+	}
+	return invitation, nil
+}
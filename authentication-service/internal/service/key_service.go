@@ -0,0 +1,309 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	keyReloadInterval = time.Minute      // Diğer replikaların yaptığı rotasyonu yakalamak için
+	keyMissReloadWait = 10 * time.Second // Bilinmeyen "kid" geldiğinde en sık yeniden yükleme aralığı
+)
+
+type loadedKey struct {
+	record  *domain.SigningKey
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+type keyService struct {
+	repo             domain.KeyRepository
+	algorithm        string
+	rotationInterval time.Duration
+	retention        time.Duration
+
+	mu         sync.RWMutex
+	keys       map[string]*loadedKey
+	active     *loadedKey
+	lastReload time.Time
+}
+
+// NewKeyService algorithm "RS256" veya "EdDSA" olabilir. retention, emekliye ayrılan
+// anahtarın JWKS'de kalma süresidir ve en uzun ömürlü token'dan kısa olmamalıdır.
+func NewKeyService(repo domain.KeyRepository, algorithm string, rotationInterval, retention time.Duration) (domain.KeyService, error) {
+	if algorithm != jwt.SigningMethodRS256.Alg() && algorithm != jwt.SigningMethodEdDSA.Alg() {
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+	return &keyService{
+		repo:             repo,
+		algorithm:        algorithm,
+		rotationInterval: rotationInterval,
+		retention:        retention,
+		keys:             map[string]*loadedKey{},
+	}, nil
+}
+
+func (s *keyService) Sign(claims jwt.Claims) (string, error) {
+	s.mu.RLock()
+	active := s.active
+	s.mu.RUnlock()
+
+	if active == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(active.record.Algorithm), claims)
+	token.Header["kid"] = active.record.ID
+
+	// RS256 imzalama *rsa.PrivateKey bekliyor
+	if rsaKey, ok := active.private.(*rsa.PrivateKey); ok {
+		return token.SignedString(rsaKey)
+	}
+	return token.SignedString(active.private)
+}
+
+func (s *keyService) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid")
+	}
+
+	key := s.lookup(kid)
+	if key == nil && s.reloadAllowed() {
+		// Başka bir replika yeni anahtar üretmiş olabilir
+		if err := s.reload(context.Background()); err != nil {
+			log.Printf("Signing key reload error: %v", err)
+		}
+		key = s.lookup(kid)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("unknown kid: %s", kid)
+	}
+
+	if token.Method.Alg() != key.record.Algorithm {
+		return nil, errors.New("signing method does not match key")
+	}
+	return key.public, nil
+}
+
+func (s *keyService) ValidMethods() []string {
+	return []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+}
+
+func (s *keyService) JWKS() *domain.JWKSet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := &domain.JWKSet{Keys: []domain.JWK{}}
+	for _, key := range s.keys {
+		set.Keys = append(set.Keys, toJWK(key))
+	}
+	return set
+}
+
+func (s *keyService) Refresh(ctx context.Context) error {
+	now := time.Now()
+	if err := s.repo.DeleteExpiredKeys(ctx, now); err != nil {
+		return err
+	}
+
+	records, err := s.repo.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := newestActiveKey(records, s.algorithm)
+	if current == nil || now.Sub(current.CreatedAt) >= s.rotationInterval {
+		if err := s.rotate(ctx, now); err != nil {
+			return err
+		}
+	}
+
+	return s.reload(ctx)
+}
+
+func (s *keyService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(keyReloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.Refresh(ctx); err != nil {
+					log.Printf("Signing key refresh error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// rotate yeni anahtar üretir ve eskileri emekliye ayırır
+func (s *keyService) rotate(ctx context.Context, now time.Time) error {
+	record, err := generateSigningKey(s.algorithm, now)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateKey(ctx, record); err != nil {
+		return err
+	}
+	if err := s.repo.RetireKeysExcept(ctx, record.ID, now, now.Add(s.retention)); err != nil {
+		return err
+	}
+
+	log.Printf("Signing key rotated, new kid: %s", record.ID)
+	return nil
+}
+
+func (s *keyService) reload(ctx context.Context) error {
+	records, err := s.repo.ListKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]*loadedKey{}
+	var active *loadedKey
+	for _, record := range records {
+		key, err := parseSigningKey(record)
+		if err != nil {
+			log.Printf("Skipping signing key %s: %v", record.ID, err)
+			continue
+		}
+		keys[record.ID] = key
+		// records created_at'e göre azalan sırada geliyor
+		if active == nil && record.RetiredAt == nil && record.Algorithm == s.algorithm {
+			active = key
+		}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	if active != nil {
+		s.active = active
+	}
+	s.lastReload = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *keyService) lookup(kid string) *loadedKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[kid]
+}
+
+func (s *keyService) reloadAllowed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.lastReload) >= keyMissReloadWait
+}
+
+func newestActiveKey(records []*domain.SigningKey, algorithm string) *domain.SigningKey {
+	for _, record := range records {
+		if record.RetiredAt == nil && record.Algorithm == algorithm {
+			return record
+		}
+	}
+	return nil
+}
+
+func generateSigningKey(algorithm string, now time.Time) (*domain.SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case jwt.SigningMethodEdDSA.Alg():
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return nil, err
+	}
+
+	return &domain.SigningKey{
+		ID:         hex.EncodeToString(kid),
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		CreatedAt:  now,
+	}, nil
+}
+
+func parseSigningKey(record *domain.SigningKey) (*loadedKey, error) {
+	block, _ := pem.Decode([]byte(record.PrivateKey))
+	if block == nil {
+		return nil, errors.New("invalid private key pem")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key is not a signer")
+	}
+
+	return &loadedKey{
+		record:  record,
+		private: private,
+		public:  private.Public(),
+	}, nil
+}
+
+func toJWK(key *loadedKey) domain.JWK {
+	jwk := domain.JWK{
+		Kid: key.record.ID,
+		Use: "sig",
+		Alg: key.record.Algorithm,
+	}
+
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return jwk
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"authentication-service/internal/totp"
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567" // 32 karakter: her byte'ın alt 5 biti eşit dağılımlı seçim yapar
+	totpAllowedSkew      = 1                                  // Saat kaymasına karşı ±1 adım (30 sn) kabul edilir
+)
+
+type mfaService struct {
+	repo          domain.UserRepository
+	issuer        string
+	requiredRoles map[string]bool
+	audit         domain.AuditLogger
+}
+
+func NewMFAService(repo domain.UserRepository, issuer string, requiredRoles []string, audit domain.AuditLogger) domain.MFAService {
+	roles := map[string]bool{}
+	for _, role := range requiredRoles {
+		roles[role] = true
+	}
+	return &mfaService{repo: repo, issuer: issuer, requiredRoles: roles, audit: audit}
+}
+
+func (s *mfaService) IsRequired(user *domain.User) bool {
+	return s.requiredRoles[user.Role]
+}
+
+func (s *mfaService) BeginEnrollment(ctx context.Context, userID string) (*domain.MFAEnrollment, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	// Kod ile doğrulanana kadar secret beklemede kalır, login akışını etkilemez
+	user.MFAPendingSecret = secret
+	if err := s.repo.Update(ctx, user, "mfa_pending_secret"); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *mfaService) ActivateEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if user.MFAPendingSecret == "" {
+		return nil, domain.ErrMFANotEnrolled
+	}
+
+	step, ok := totp.Validate(user.MFAPendingSecret, code, time.Now(), totpAllowedSkew)
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabled = true
+	user.MFASecret = user.MFAPendingSecret
+	user.MFAPendingSecret = ""
+	user.MFARecoveryCodes = hashes
+	user.MFALastStep = step
+	if err := s.repo.Update(ctx, user, "mfa_enabled", "mfa_secret", "mfa_pending_secret", "mfa_recovery_codes", "mfa_last_step"); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditMFAEnable, ActorID: userID, ActorEmail: user.Email, TargetType: domain.AuditTargetUser, TargetID: userID})
+	return codes, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, userID, code string) error {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return domain.ErrMFANotEnabled
+	}
+	if s.IsRequired(user) {
+		return domain.ErrMFARequiredForRole
+	}
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = ""
+	user.MFAPendingSecret = ""
+	user.MFARecoveryCodes = nil
+	if err := s.repo.Update(ctx, user, "mfa_enabled", "mfa_secret", "mfa_pending_secret", "mfa_recovery_codes"); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditMFADisable, TargetType: domain.AuditTargetUser, TargetID: userID})
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, domain.ErrMFANotEnabled
+	}
+	if err := s.verifyTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	user.MFARecoveryCodes = hashes
+	if err := s.repo.Update(ctx, user, "mfa_recovery_codes"); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *mfaService) VerifyLogin(ctx context.Context, user *domain.User, code, recoveryCode string) error {
+	if !user.MFAEnabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	if recoveryCode != "" {
+		consumed, err := s.repo.ConsumeRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return domain.ErrInvalidMFACode
+		}
+		return nil
+	}
+	return s.verifyTOTP(ctx, user, code)
+}
+
+// verifyTOTP kodu doğrular ve adımı tüketir; başarılı olursa user.MFALastStep güncellenir
+// ki sonrasında yapılan Update eski değeri geri yazmasın
+func (s *mfaService) verifyTOTP(ctx context.Context, user *domain.User, code string) error {
+	step, ok := totp.Validate(user.MFASecret, code, time.Now(), totpAllowedSkew)
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	consumed, err := s.repo.ConsumeMFAStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		// Kod daha önce kullanılmış (replay)
+		return domain.ErrInvalidMFACode
+	}
+	user.MFALastStep = step
+	return nil
+}
+
+// generateRecoveryCodes kullanıcıya gösterilecek kodları ve veritabanına yazılacak hash'lerini döner
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	buf := make([]byte, 10)
+	for i := range codes {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, errors.New("error generating recovery codes")
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(recoveryCodeAlphabet[b&31])
+		}
+		codes[i] = sb.String()
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"authentication-service/internal/totp"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stepRepository ConsumeMFAStep'in Mongo'daki koşullu güncellemesini taklit eder
+type stepRepository struct {
+	domain.UserRepository
+	lastStep int64
+}
+
+func (r *stepRepository) ConsumeMFAStep(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	if step <= r.lastStep {
+		return false, nil
+	}
+	r.lastStep = step
+	return true, nil
+}
+
+func TestVerifyLoginRejectsReusedStep(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &stepRepository{}
+	mfa := NewMFAService(repo, "test", nil, nil)
+	user := &domain.User{ID: primitive.NewObjectID(), MFAEnabled: true, MFASecret: secret}
+
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	if err := mfa.VerifyLogin(context.Background(), user, code, ""); err != nil {
+		t.Fatalf("first use rejected: %v", err)
+	}
+	if err := mfa.VerifyLogin(context.Background(), user, code, ""); !errors.Is(err, domain.ErrInvalidMFACode) {
+		t.Fatalf("reused code: got %v, want ErrInvalidMFACode", err)
+	}
+
+	// Pencere içindeki daha eski bir adımın kodu da tüketilen adımdan sonra kabul edilmez
+	previous, _ := totp.Code(secret, totp.Step(time.Now())-1)
+	if err := mfa.VerifyLogin(context.Background(), user, previous, ""); !errors.Is(err, domain.ErrInvalidMFACode) {
+		t.Fatalf("older step after newer one: got %v, want ErrInvalidMFACode", err)
+	}
+}
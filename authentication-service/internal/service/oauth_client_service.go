@@ -0,0 +1,136 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+type oauthClientService struct {
+	repo     domain.OAuthClientRepository
+	sessions domain.SessionRepository
+	audit    domain.AuditLogger
+}
+
+func NewOAuthClientService(repo domain.OAuthClientRepository, sessions domain.SessionRepository, audit domain.AuditLogger) domain.OAuthClientService {
+	return &oauthClientService{
+		repo:     repo,
+		sessions: sessions,
+		audit:    audit,
+	}
+}
+
+func (s *oauthClientService) Register(ctx context.Context, client *domain.OAuthClient) (string, error) {
+	client.Name = strings.TrimSpace(client.Name)
+	if client.Name == "" {
+		return "", errors.New("client name is required")
+	}
+	// Kullanıcı girişi için redirect_uri, servis token'ı için scope gerekir; ikisi birden de olabilir
+	if len(client.RedirectURIs) == 0 && len(client.Scopes) == 0 {
+		return "", errors.New("at least one redirect uri or service scope is required")
+	}
+	for _, uri := range client.RedirectURIs {
+		if !validRedirectURI(uri) {
+			return "", domain.ErrInvalidRedirectURI
+		}
+	}
+	if err := validateServiceScopes(client.Scopes); err != nil {
+		return "", err
+	}
+	if client.Public && len(client.Scopes) > 0 {
+		return "", errors.New("public clients cannot use service scopes")
+	}
+	if client.RedirectURIs == nil {
+		client.RedirectURIs = []string{}
+	}
+
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	client.ID = hex.EncodeToString(id)
+
+	// Public istemciler (SPA, mobil) secret saklayamaz; onlar için sadece PKCE kullanılır
+	var secret string
+	if !client.Public {
+		var err error
+		if secret, err = generateRandomToken(); err != nil {
+			return "", err
+		}
+		client.SecretHash = hashToken(secret)
+	}
+
+	if err := s.repo.Create(ctx, client); err != nil {
+		return "", err
+	}
+
+	_, after := auditDiff(nil, client)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditOAuthClientCreate, TargetType: domain.AuditTargetOAuthClient, TargetID: client.ID, After: after})
+	return secret, nil
+}
+
+// EnsureServiceClient secret ve scope'ları ortam değişkenlerinden gelen servis istemcisini her açılışta
+// günceller; böylece servisler arası kimlik admin paneline girmeden kurulabilir
+func (s *oauthClientService) EnsureServiceClient(ctx context.Context, id, secret string, scopes []string) error {
+	if err := validateServiceScopes(scopes); err != nil {
+		return err
+	}
+	return s.repo.Upsert(ctx, &domain.OAuthClient{
+		ID:         id,
+		Name:       id,
+		SecretHash: hashToken(secret),
+		Scopes:     scopes,
+		CreatedBy:  "system",
+	})
+}
+
+func (s *oauthClientService) List(ctx context.Context) ([]*domain.OAuthClient, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *oauthClientService) Get(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Delete istemciyi kaldırır ve istemciye verilmiş tüm oturumları kapatır
+func (s *oauthClientService) Delete(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeClientSessions(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditOAuthClientDelete, TargetType: domain.AuditTargetOAuthClient, TargetID: id})
+	return nil
+}
+
+func validateServiceScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !slices.Contains(domain.ServiceScopes, scope) {
+			return fmt.Errorf("%w: %s", domain.ErrInvalidServiceScope, scope)
+		}
+	}
+	return nil
+}
+
+// validRedirectURI kodun ele geçirilmemesi için https ister; http sadece yerel geliştirmede kabul edilir
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || strings.Contains(raw, "#") {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	}
+	return false
+}
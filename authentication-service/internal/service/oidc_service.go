@@ -0,0 +1,482 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDCConfig OpenID Connect sağlayıcı ayarları
+type OIDCConfig struct {
+	Issuer         string        // Discovery ve ID token'lardaki "iss"
+	CodeTTL        time.Duration // Yetkilendirme kodunun geçerlilik süresi
+	AccessTokenTTL time.Duration // ID token da access token kadar geçerlidir
+}
+
+type oidcService struct {
+	clients  domain.OAuthClientRepository
+	codes    domain.AuthorizationCodeRepository
+	users    domain.UserRepository
+	sessions domain.SessionRepository
+	auth     domain.AuthService
+	keys     domain.KeyService
+	apiKeys  domain.APIKeyService
+	cfg      OIDCConfig
+}
+
+func NewOIDCService(clients domain.OAuthClientRepository, codes domain.AuthorizationCodeRepository, users domain.UserRepository, sessions domain.SessionRepository, auth domain.AuthService, keys domain.KeyService, apiKeys domain.APIKeyService, cfg OIDCConfig) domain.OIDCService {
+	return &oidcService{
+		clients:  clients,
+		codes:    codes,
+		users:    users,
+		sessions: sessions,
+		auth:     auth,
+		keys:     keys,
+		apiKeys:  apiKeys,
+		cfg:      cfg,
+	}
+}
+
+func (s *oidcService) Discovery() *domain.OIDCDiscovery {
+	return &domain.OIDCDiscovery{
+		Issuer:                            s.cfg.Issuer,
+		AuthorizationEndpoint:             s.cfg.Issuer + "/oauth/authorize",
+		TokenEndpoint:                     s.cfg.Issuer + "/oauth/token",
+		UserInfoEndpoint:                  s.cfg.Issuer + "/oauth/userinfo",
+		IntrospectionEndpoint:             s.cfg.Issuer + "/oauth/introspect",
+		JWKSURI:                           s.cfg.Issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   slices.Concat(domain.OIDCScopes, domain.ServiceScopes),
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{domain.GrantAuthorizationCode, domain.GrantRefreshToken, domain.GrantClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  s.keys.ValidMethods(),
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{domain.CodeChallengeS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "amr", "email", "email_verified", "name", "given_name", "family_name"},
+	}
+}
+
+func (s *oidcService) ValidateAuthorization(ctx context.Context, req domain.AuthorizationRequest) (*domain.OAuthClient, []string, error) {
+	// İstemci ve redirect_uri doğrulanmadan hiçbir hata yönlendirme ile dönülmez (open redirect)
+	client, err := s.clients.Get(ctx, req.ClientID)
+	if err != nil {
+		return nil, nil, domain.NewOAuthError(domain.OAuthInvalidClient, "unknown client_id")
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	if req.ResponseType != "code" {
+		return client, nil, domain.NewOAuthError(domain.OAuthUnsupportedResponseType, "only response_type=code is supported")
+	}
+	// PKCE gizli istemciler için de zorunlu; kod redirect sırasında sızsa bile token'a çevrilemez
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != domain.CodeChallengeS256 {
+		return client, nil, domain.NewOAuthError(domain.OAuthInvalidRequest, "code_challenge with code_challenge_method=S256 is required")
+	}
+	scopes, err := parseScopes(req.Scope)
+	if err != nil {
+		return client, nil, err
+	}
+	return client, scopes, nil
+}
+
+func (s *oidcService) IssueCode(ctx context.Context, req domain.AuthorizationRequest, user *domain.User, amr []string) (string, error) {
+	_, scopes, err := s.ValidateAuthorization(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := generateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = s.codes.Create(ctx, &domain.AuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      req.ClientID,
+		UserID:        user.ID.Hex(),
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AMR:           amr,
+		AuthTime:      now,
+		ExpiresAt:     now.Add(s.cfg.CodeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+func (s *oidcService) Token(ctx context.Context, req domain.TokenRequest, info domain.ClientInfo) (*domain.OIDCTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case domain.GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req, info)
+	case domain.GrantRefreshToken:
+		return s.refresh(ctx, client, req, info)
+	case domain.GrantClientCredentials:
+		return s.clientCredentials(client, req.Scope)
+	default:
+		return nil, domain.NewOAuthError(domain.OAuthUnsupportedGrantType, "")
+	}
+}
+
+func (s *oidcService) exchangeCode(ctx context.Context, client *domain.OAuthClient, req domain.TokenRequest, info domain.ClientInfo) (*domain.OIDCTokenResponse, error) {
+	invalidGrant := domain.NewOAuthError(domain.OAuthInvalidGrant, "invalid or expired authorization code")
+
+	code, err := s.codes.GetByHash(ctx, hashToken(req.Code))
+	if err != nil || code.ClientID != client.ID {
+		return nil, invalidGrant
+	}
+
+	marked, err := s.codes.MarkUsed(ctx, code.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		// Kod ikinci kez kullanıldı: ele geçirilmiş sayılır, ilk kullanımda açılan oturum kapatılır
+		if code.SessionID != nil {
+			_ = s.sessions.RevokeSession(ctx, *code.SessionID)
+		}
+		return nil, invalidGrant
+	}
+	if time.Now().After(code.ExpiresAt) || code.RedirectURI != req.RedirectURI {
+		return nil, invalidGrant
+	}
+	if !verifyCodeChallenge(req.CodeVerifier, code.CodeChallenge) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, "code_verifier does not match code_challenge")
+	}
+
+	session := &domain.Session{UserID: code.UserID, AMR: code.AMR, ClientID: client.ID, Scopes: code.Scopes, Delegated: !client.FirstParty}
+	result, err := s.auth.StartClientSession(ctx, session, info)
+	if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrOrganizationInactive) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codes.SetSession(ctx, code.ID, session.ID); err != nil {
+		log.Printf("Authorization code session link error: %v", err)
+	}
+
+	idToken, err := s.generateIDToken(result.User, client.ID, code)
+	if err != nil {
+		return nil, errors.New("error generating token")
+	}
+	return s.tokenResponse(result.Tokens, code.Scopes, idToken), nil
+}
+
+func (s *oidcService) refresh(ctx context.Context, client *domain.OAuthClient, req domain.TokenRequest, info domain.ClientInfo) (*domain.OIDCTokenResponse, error) {
+	invalidGrant := domain.NewOAuthError(domain.OAuthInvalidGrant, domain.ErrInvalidRefreshToken.Error())
+
+	// Başka bir istemcinin (veya kendi arayüzümüzün) refresh token'ı bu uçtan kullanılamaz
+	stored, err := s.sessions.GetRefreshTokenByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		return nil, invalidGrant
+	}
+	session, err := s.sessions.GetSession(ctx, stored.SessionID.Hex())
+	if err != nil || session.ClientID != client.ID {
+		return nil, invalidGrant
+	}
+
+	tokens, err := s.auth.Refresh(ctx, req.RefreshToken, info)
+	if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) || errors.Is(err, domain.ErrOrganizationInactive) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidGrant, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.tokenResponse(tokens, session.Scopes, ""), nil
+}
+
+// clientCredentials servis istemcisine kullanıcıya bağlı olmayan, oturumsuz bir token verir.
+// Token iptal edilemez; istemci silinirse introspection ve servis uçları token'ı reddeder.
+func (s *oidcService) clientCredentials(client *domain.OAuthClient, requested string) (*domain.OIDCTokenResponse, error) {
+	if client.Public || len(client.Scopes) == 0 {
+		return nil, domain.NewOAuthError(domain.OAuthUnauthorizedClient, "client is not allowed to use client_credentials")
+	}
+
+	scopes := client.Scopes
+	if requested != "" {
+		scopes = []string{}
+		for _, scope := range strings.Fields(requested) {
+			if !slices.Contains(client.Scopes, scope) {
+				return nil, domain.NewOAuthError(domain.OAuthInvalidScope, "scope is not allowed for this client: "+scope)
+			}
+			if !slices.Contains(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	now := time.Now()
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"typ":       tokenTypeClient,
+		"iss":       s.cfg.Issuer,
+		"sub":       client.ID,
+		"client_id": client.ID,
+		"scope":     strings.Join(scopes, " "),
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.cfg.AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, errors.New("error generating token")
+	}
+	return &domain.OIDCTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.cfg.AccessTokenTTL.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+func (s *oidcService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*domain.TokenIntrospection, error) {
+	caller, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(caller.Scopes, domain.ScopeTokenIntrospect) {
+		return nil, domain.NewOAuthError(domain.OAuthUnauthorizedClient, "client is not allowed to introspect tokens")
+	}
+
+	inactive := &domain.TokenIntrospection{Active: false}
+	if strings.HasPrefix(token, domain.APIKeyPrefix) {
+		return s.introspectAPIKey(ctx, token)
+	}
+	claims, err := s.parseToken(token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	result := &domain.TokenIntrospection{
+		Active:    true,
+		TokenType: "Bearer",
+		Iss:       s.cfg.Issuer,
+		Kind:      claimValue(claims, "typ"),
+		Scope:     claimValue(claims, "scope"),
+		ClientID:  claimValue(claims, "client_id"),
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.Exp = exp.Unix()
+	}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.Iat = iat.Unix()
+	}
+
+	switch result.Kind {
+	case tokenTypeAccess, tokenTypeDelegated:
+		// İmza geçerli olsa da logout veya iptal edilen oturumun token'ı aktif sayılmaz
+		result.SessionID = claimValue(claims, "sid")
+		session, err := s.sessions.GetSession(ctx, result.SessionID)
+		if err != nil || session.RevokedAt != nil {
+			return inactive, nil
+		}
+		result.Sub = claimValue(claims, "user_id")
+		result.Username = claimValue(claims, "email")
+		result.Role = claimValue(claims, "role")
+		result.Permissions = claimValues(claims, "permissions")
+		result.OrgID = domain.UserOrganization(claimValue(claims, "org"))
+		if session.ImpersonatorID != "" {
+			result.Act = &domain.Actor{Sub: session.ImpersonatorID, Email: session.ImpersonatorEmail}
+		}
+	case tokenTypeClient:
+		if _, err := s.clients.Get(ctx, result.ClientID); err != nil {
+			return inactive, nil
+		}
+		result.Sub = result.ClientID
+	default:
+		// MFA challenge, doğrulama linki ve ID token'lar API erişimi için kullanılamaz
+		return inactive, nil
+	}
+	return result, nil
+}
+
+// introspectAPIKey X-API-Key ile gelen anahtarları token gibi sorgulatır; servisler
+// anahtarın kimliğini ve kesişim yetkilerini buradan alır
+func (s *oidcService) introspectAPIKey(ctx context.Context, key string) (*domain.TokenIntrospection, error) {
+	identity, err := s.apiKeys.Authenticate(ctx, key)
+	if errors.Is(err, domain.ErrInvalidAPIKey) || errors.Is(err, domain.ErrOrganizationInactive) {
+		return &domain.TokenIntrospection{Active: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TokenIntrospection{
+		Active:      true,
+		Iss:         s.cfg.Issuer,
+		Kind:        tokenTypeAPIKey,
+		Sub:         identity.UserID,
+		Username:    identity.Email,
+		Role:        identity.Role,
+		Permissions: identity.Permissions,
+		OrgID:       identity.OrgID,
+		Exp:         identity.ExpiresAt.Unix(),
+		Iat:         identity.CreatedAt.Unix(),
+	}, nil
+}
+
+func (s *oidcService) VerifyClientToken(ctx context.Context, token, scope string) (*domain.OAuthClient, error) {
+	claims, err := s.parseToken(token)
+	if err != nil || claimValue(claims, "typ") != tokenTypeClient {
+		return nil, domain.ErrInvalidClientToken
+	}
+	client, err := s.clients.Get(ctx, claimValue(claims, "client_id"))
+	if err != nil {
+		return nil, domain.ErrInvalidClientToken
+	}
+	if scope != "" && !slices.Contains(strings.Fields(claimValue(claims, "scope")), scope) {
+		return nil, domain.ErrInsufficientScope
+	}
+	return client, nil
+}
+
+func (s *oidcService) parseToken(token string) (jwt.MapClaims, error) {
+	parsed, err := jwt.Parse(token, s.keys.Keyfunc, jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return nil, errors.New("invalid token")
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+	return claims, nil
+}
+
+func (s *oidcService) UserInfo(ctx context.Context, userID string, scopes []string) (map[string]interface{}, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return userClaims(user, scopes), nil
+}
+
+// authenticateClient gizli istemcinin secret'ını doğrular; public istemciler secret göndermez
+func (s *oidcService) authenticateClient(ctx context.Context, clientID, secret string) (*domain.OAuthClient, error) {
+	invalidClient := domain.NewOAuthError(domain.OAuthInvalidClient, "client authentication failed")
+
+	client, err := s.clients.Get(ctx, clientID)
+	if err != nil {
+		return nil, invalidClient
+	}
+	if client.Public {
+		if secret != "" {
+			return nil, invalidClient
+		}
+		return client, nil
+	}
+	if secret == "" || subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, invalidClient
+	}
+	return client, nil
+}
+
+func (s *oidcService) generateIDToken(user *domain.User, clientID string, code *domain.AuthorizationCode) (string, error) {
+	now := time.Now()
+	// "typ" claim'i yok: ID token API'lerde access token olarak kabul edilmez
+	claims := jwt.MapClaims{
+		"iss":       s.cfg.Issuer,
+		"sub":       user.ID.Hex(),
+		"aud":       clientID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.cfg.AccessTokenTTL).Unix(),
+		"auth_time": code.AuthTime.Unix(),
+		"amr":       code.AMR,
+	}
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+	for key, value := range userClaims(user, code.Scopes) {
+		claims[key] = value
+	}
+	return s.keys.Sign(claims)
+}
+
+func (s *oidcService) tokenResponse(tokens *domain.TokenPair, scopes []string, idToken string) *domain.OIDCTokenResponse {
+	resp := &domain.OIDCTokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   tokens.ExpiresIn,
+		IDToken:     idToken,
+		Scope:       strings.Join(scopes, " "),
+	}
+	// Refresh token sadece istemci çevrimdışı erişim istediyse verilir
+	if slices.Contains(scopes, domain.ScopeOfflineAccess) {
+		resp.RefreshToken = tokens.RefreshToken
+	}
+	return resp
+}
+
+// userClaims onaylanan scope'lara göre ID token ve userinfo claim'lerini üretir
+func userClaims(user *domain.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{"sub": user.ID.Hex()}
+	if slices.Contains(scopes, domain.ScopeProfile) {
+		claims["name"] = strings.TrimSpace(user.FirstName + " " + user.LastName)
+		claims["given_name"] = user.FirstName
+		claims["family_name"] = user.LastName
+	}
+	if slices.Contains(scopes, domain.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = !user.VerificationPending
+	}
+	return claims
+}
+
+// parseScopes boşlukla ayrılmış scope listesini doğrular; "openid" zorunludur
+func parseScopes(raw string) ([]string, error) {
+	scopes := []string{}
+	for _, scope := range strings.Fields(raw) {
+		if !slices.Contains(domain.OIDCScopes, scope) {
+			return nil, domain.NewOAuthError(domain.OAuthInvalidScope, "unsupported scope: "+scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if !slices.Contains(scopes, domain.ScopeOpenID) {
+		return nil, domain.NewOAuthError(domain.OAuthInvalidScope, "openid scope is required")
+	}
+	return scopes, nil
+}
+
+func claimValue(claims jwt.MapClaims, key string) string {
+	value, _ := claims[key].(string)
+	return value
+}
+
+// claimValues JSON dizisi olarak gelen claim'i ([]interface{}) string listesine çevirir
+func claimValues(claims jwt.MapClaims, key string) []string {
+	raw, _ := claims[key].([]interface{})
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// verifyCodeChallenge RFC 7636 S256: BASE64URL(SHA256(code_verifier)) == code_challenge
+func verifyCodeChallenge(verifier, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RFC 7636 Ek B
+const (
+	rfcCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+const testRedirectURI = "https://app.example.com/callback"
+
+type clientRepository struct {
+	domain.OAuthClientRepository
+	client *domain.OAuthClient
+}
+
+func (r *clientRepository) Get(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	if id != r.client.ID {
+		return nil, errors.New("client not found")
+	}
+	return r.client, nil
+}
+
+type codeRepository struct {
+	domain.AuthorizationCodeRepository
+	code *domain.AuthorizationCode
+}
+
+func (r *codeRepository) GetByHash(ctx context.Context, hash string) (*domain.AuthorizationCode, error) {
+	if hash != r.code.CodeHash {
+		return nil, errors.New("code not found")
+	}
+	return r.code, nil
+}
+
+func (r *codeRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	return true, nil
+}
+
+func newTestOIDCService(code *domain.AuthorizationCode) domain.OIDCService {
+	clients := &clientRepository{client: &domain.OAuthClient{ID: "app", Public: true, RedirectURIs: []string{testRedirectURI}}}
+	return NewOIDCService(clients, &codeRepository{code: code}, nil, nil, nil, nil, nil, OIDCConfig{CodeTTL: time.Minute})
+}
+
+func oauthErrorCode(err error) string {
+	var oauthErr *domain.OAuthError
+	if errors.As(err, &oauthErr) {
+		return oauthErr.Code
+	}
+	return ""
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		ok        bool
+	}{
+		{"rfc vector", rfcCodeVerifier, rfcCodeChallenge, true},
+		{"wrong verifier", "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXK", rfcCodeChallenge, false},
+		{"plain method", rfcCodeVerifier, rfcCodeVerifier, false},
+		{"padded challenge", rfcCodeVerifier, rfcCodeChallenge + "=", false},
+		{"short verifier", "abc", rfcCodeChallenge, false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.verifier, tt.challenge); got != tt.ok {
+				t.Errorf("verifyCodeChallenge = %v, want %v", got, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateAuthorizationRedirectURIExactMatch(t *testing.T) {
+	svc := newTestOIDCService(nil)
+	tests := []struct {
+		redirectURI string
+		ok          bool
+	}{
+		{testRedirectURI, true},
+		{testRedirectURI + "/", false},
+		{testRedirectURI + "?next=/admin", false},
+		{testRedirectURI + "/../evil", false},
+		{"https://APP.example.com/callback", false},
+		{"http://app.example.com/callback", false},
+		{"https://app.example.com.evil.com/callback", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.redirectURI, func(t *testing.T) {
+			client, _, err := svc.ValidateAuthorization(context.Background(), domain.AuthorizationRequest{
+				ResponseType:        "code",
+				ClientID:            "app",
+				RedirectURI:         tt.redirectURI,
+				Scope:               domain.ScopeOpenID,
+				CodeChallenge:       rfcCodeChallenge,
+				CodeChallengeMethod: domain.CodeChallengeS256,
+			})
+			if tt.ok && err != nil {
+				t.Fatalf("registered redirect_uri rejected: %v", err)
+			}
+			if !tt.ok {
+				// Kayıtlı olmayan adrese yönlendirme yapılmaması için istemci dönülmez
+				if oauthErrorCode(err) != domain.OAuthInvalidRequest || client != nil {
+					t.Fatalf("got client=%v err=%v, want invalid_request without client", client, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAuthorizationRequiresS256(t *testing.T) {
+	svc := newTestOIDCService(nil)
+	for _, method := range []string{"", "plain", "s256"} {
+		_, _, err := svc.ValidateAuthorization(context.Background(), domain.AuthorizationRequest{
+			ResponseType:        "code",
+			ClientID:            "app",
+			RedirectURI:         testRedirectURI,
+			Scope:               domain.ScopeOpenID,
+			CodeChallenge:       rfcCodeChallenge,
+			CodeChallengeMethod: method,
+		})
+		if oauthErrorCode(err) != domain.OAuthInvalidRequest {
+			t.Errorf("code_challenge_method=%q: got %v, want invalid_request", method, err)
+		}
+	}
+}
+
+func TestExchangeCodeRejectsMismatch(t *testing.T) {
+	code := &domain.AuthorizationCode{
+		ID:            primitive.NewObjectID(),
+		CodeHash:      hashToken("the-code"),
+		ClientID:      "app",
+		RedirectURI:   testRedirectURI,
+		CodeChallenge: rfcCodeChallenge,
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	svc := newTestOIDCService(code)
+
+	tests := []struct {
+		name        string
+		redirectURI string
+		verifier    string
+	}{
+		{"different redirect_uri", testRedirectURI + "/", rfcCodeVerifier},
+		{"missing redirect_uri", "", rfcCodeVerifier},
+		{"wrong code_verifier", testRedirectURI, "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXK"},
+		{"missing code_verifier", testRedirectURI, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Token(context.Background(), domain.TokenRequest{
+				GrantType:    domain.GrantAuthorizationCode,
+				Code:         "the-code",
+				RedirectURI:  tt.redirectURI,
+				CodeVerifier: tt.verifier,
+				ClientID:     "app",
+			}, domain.ClientInfo{})
+			if oauthErrorCode(err) != domain.OAuthInvalidGrant {
+				t.Fatalf("got %v, want invalid_grant", err)
+			}
+		})
+	}
+}
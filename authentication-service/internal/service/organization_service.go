@@ -0,0 +1,85 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Organizasyon ID'si token'da ve waste servisi kayıtlarında taşındığı için kısa bir slug olmalı
+var organizationIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,47}$`)
+
+type organizationService struct {
+	repo  domain.OrganizationRepository
+	audit domain.AuditLogger
+}
+
+func NewOrganizationService(repo domain.OrganizationRepository, audit domain.AuditLogger) domain.OrganizationService {
+	return &organizationService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+func (s *organizationService) List(ctx context.Context) ([]*domain.Organization, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *organizationService) Get(ctx context.Context, id string) (*domain.Organization, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *organizationService) Create(ctx context.Context, org *domain.Organization) error {
+	if !organizationIDPattern.MatchString(org.ID) {
+		return domain.ErrInvalidOrganizationID
+	}
+	org.Name = strings.TrimSpace(org.Name)
+	if org.Name == "" {
+		return errors.New("organization name is required")
+	}
+	org.Active = true
+	if err := s.repo.Create(ctx, org); err != nil {
+		return err
+	}
+
+	_, after := auditDiff(nil, org)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditOrganizationCreate, TargetType: domain.AuditTargetOrganization, TargetID: org.ID, After: after})
+	return nil
+}
+
+func (s *organizationService) Update(ctx context.Context, id string, org *domain.Organization) error {
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	previous := *existing
+
+	if name := strings.TrimSpace(org.Name); name != "" {
+		existing.Name = name
+	}
+	// Varsayılan organizasyon kayıt akışında kullanıldığı için kapatılamaz
+	if !org.Active && id == domain.DefaultOrganizationID {
+		return errors.New("default organization cannot be deactivated")
+	}
+	existing.Active = org.Active
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return err
+	}
+
+	before, after := auditDiff(&previous, existing)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditOrganizationUpdate, TargetType: domain.AuditTargetOrganization, TargetID: id, Before: before, After: after})
+	return nil
+}
+
+func (s *organizationService) Resolve(ctx context.Context, id string) (*domain.Organization, error) {
+	org, err := s.repo.Get(ctx, domain.UserOrganization(id))
+	if err != nil {
+		return nil, err
+	}
+	if !org.Active {
+		return nil, domain.ErrOrganizationInactive
+	}
+	return org, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+type passwordService struct {
+	repo       domain.UserRepository
+	tokens     domain.OneTimeTokenRepository
+	sessions   domain.SessionRepository
+	mailer     domain.Mailer
+	appBaseURL string
+	resetTTL   time.Duration
+	audit      domain.AuditLogger
+}
+
+func NewPasswordService(repo domain.UserRepository, tokens domain.OneTimeTokenRepository, sessions domain.SessionRepository, mailer domain.Mailer, appBaseURL string, resetTTL time.Duration, audit domain.AuditLogger) domain.PasswordService {
+	return &passwordService{
+		repo:       repo,
+		tokens:     tokens,
+		sessions:   sessions,
+		mailer:     mailer,
+		appBaseURL: strings.TrimRight(appBaseURL, "/"),
+		resetTTL:   resetTTL,
+		audit:      audit,
+	}
+}
+
+// ForgotPassword kayıtlı ve aktif bir hesap varsa sıfırlama linki gönderir.
+// Hesabın var olup olmadığı dışarıya sızmasın diye kullanıcı bulunamadığında da hata dönmez.
+func (s *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return nil
+	}
+
+	userID := user.ID.Hex()
+	// Önceki linkler geçersiz olsun, sadece en son gönderilen çalışsın
+	if err := s.tokens.DeleteUserTokens(ctx, userID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		return err
+	}
+	err = s.tokens.Create(ctx, &domain.OneTimeToken{
+		UserID:    userID,
+		Purpose:   domain.TokenPurposePasswordReset,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.appBaseURL, url.QueryEscape(token))
+	err = s.mailer.Send(ctx, domain.MailMessage{
+		To:      user.Email,
+		Subject: "Şifre sıfırlama talebi",
+		Body: fmt.Sprintf("Merhaba %s,\n\nŞifrenizi sıfırlamak için aşağıdaki linki kullanın:\n%s\n\n"+
+			"Link %d dakika boyunca ve yalnızca bir kez geçerlidir. Bu talebi siz yapmadıysanız bu e-postayı dikkate almayın.\n",
+			user.FirstName, link, int(s.resetTTL.Minutes())),
+	})
+	if err != nil {
+		log.Printf("Password reset mail error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ResetPassword token'ı tüketir, şifreyi değiştirir ve kullanıcının tüm oturumlarını kapatır
+func (s *passwordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if len(newPassword) < minPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	stored, err := s.tokens.GetByHash(ctx, domain.TokenPurposePasswordReset, hashToken(token))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return domain.ErrInvalidOneTimeToken
+	}
+
+	user, err := s.repo.GetByID(ctx, stored.UserID)
+	if err != nil || !user.Active || user.DeletedAt != nil {
+		return domain.ErrInvalidOneTimeToken
+	}
+
+	marked, err := s.tokens.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return domain.ErrInvalidOneTimeToken
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+	if err := s.repo.Update(ctx, user, "password"); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPasswordReset, ActorID: stored.UserID, ActorEmail: user.Email, TargetType: domain.AuditTargetUser, TargetID: stored.UserID})
+
+	// Şifre değiştiği için eski oturumlar (ele geçirilmiş olabilir) kapatılır
+	return s.sessions.RevokeUserSessions(ctx, stored.UserID)
+}
+
+// ChangePassword oturum açmış kullanıcının şifresini mevcut şifre onayıyla değiştirir.
+// İsteği yapan oturum açık kalır, diğer cihazlardaki oturumlar kapatılır.
+func (s *passwordService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword, currentSessionID string) error {
+	if len(newPassword) < minPasswordLength {
+		return domain.ErrWeakPassword
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || user.DeletedAt != nil {
+		return domain.ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return domain.ErrWrongPassword
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+	if err := s.repo.Update(ctx, user, "password"); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPasswordChange, TargetType: domain.AuditTargetUser, TargetID: userID})
+
+	keep, err := primitive.ObjectIDFromHex(currentSessionID)
+	if err != nil {
+		return s.sessions.RevokeUserSessions(ctx, userID)
+	}
+	return s.sessions.RevokeOtherSessions(ctx, userID, keep)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"archive/zip"
+	"authentication-service/internal/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type privacyService struct {
+	users     domain.UserRepository
+	retention domain.RetentionService
+	erasures  domain.ErasureRepository
+	waste     domain.WasteClient
+	audit     domain.AuditLogger
+}
+
+func NewPrivacyService(users domain.UserRepository, retention domain.RetentionService, erasures domain.ErasureRepository, waste domain.WasteClient, audit domain.AuditLogger) domain.PrivacyService {
+	return &privacyService{
+		users:     users,
+		retention: retention,
+		erasures:  erasures,
+		waste:     waste,
+		audit:     audit,
+	}
+}
+
+// Export ZIP düzeni: account.json (auth servisi) ve waste/ altında waste servisinin ZIP'inin içeriği
+func (s *privacyService) Export(ctx context.Context, userID string, w io.Writer) error {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Waste verisi olmadan eksik bir dışa aktarma üretilmez; yazmaya başlamadan önce alınır
+	wasteZip, err := s.waste.Export(ctx, userID)
+	if err != nil {
+		return err
+	}
+	wasteArchive, err := zip.NewReader(bytes.NewReader(wasteZip), int64(len(wasteZip)))
+	if err != nil {
+		return fmt.Errorf("%w: invalid export archive: %v", domain.ErrWasteServiceUnavailable, err)
+	}
+
+	archive := zip.NewWriter(w)
+	account, err := archive.Create("account.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(account)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(map[string]interface{}{"exported_at": time.Now(), "user": user}); err != nil {
+		return err
+	}
+
+	for _, f := range wasteArchive.File {
+		if err := copyZipEntry(archive, f, "waste/"+f.Name); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPrivacyExport, TargetType: domain.AuditTargetUser, TargetID: userID})
+	return nil
+}
+
+func (s *privacyService) Erase(ctx context.Context, userID, password string) (*domain.ErasureRequest, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, domain.ErrWrongPassword
+	}
+
+	request := &domain.ErasureRequest{
+		UserID:      userID,
+		OrgID:       domain.UserOrganization(user.OrgID),
+		Status:      domain.ErasureStatusPending,
+		RequestedAt: time.Now(),
+	}
+	if err := s.erasures.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	// Sıra önemli: hesap en son silinir. Waste tarafı yarıda kalırsa hesap durur ve
+	// kullanıcı talebi tekrarlayabilir.
+	result, err := s.waste.Erase(ctx, userID)
+	if err != nil {
+		return nil, s.fail(ctx, request, err)
+	}
+	request.WastesAnonymized = result.WastesAnonymized
+	request.RequestsAnonymized = result.RequestsAnonymized
+	request.ImagesDeleted = result.ImagesDeleted
+
+	if err := s.users.Delete(ctx, userID); err != nil {
+		return nil, s.fail(ctx, request, err)
+	}
+	if err := s.retention.Purge(ctx, userID); err != nil {
+		return nil, s.fail(ctx, request, err)
+	}
+
+	now := time.Now()
+	request.Status = domain.ErasureStatusCompleted
+	request.CompletedAt = &now
+	if err := s.erasures.Update(ctx, request); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditPrivacyErase,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID,
+		Metadata:   map[string]interface{}{"erasure_id": request.ID.Hex()},
+	})
+	return request, nil
+}
+
+func (s *privacyService) ListErasures(ctx context.Context, orgID string, limit int64) ([]*domain.ErasureRequest, error) {
+	return s.erasures.List(ctx, orgID, limit)
+}
+
+func (s *privacyService) activeUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil || user.DeletedAt != nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (s *privacyService) fail(ctx context.Context, request *domain.ErasureRequest, cause error) error {
+	request.Status = domain.ErasureStatusFailed
+	request.Error = cause.Error()
+	if err := s.erasures.Update(ctx, request); err != nil {
+		return fmt.Errorf("%v (erasure record update failed: %v)", cause, err)
+	}
+	return cause
+}
+
+func copyZipEntry(archive *zip.Writer, f *zip.File, name string) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: f.Modified})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tek seferde işlenen kullanıcı sayısı; bir çalıştırma tüm birikmiş kayıtları batch'ler halinde temizler
+const retentionBatchSize = 100
+
+type retentionService struct {
+	users       domain.UserRepository
+	sessions    domain.SessionRepository
+	tokens      domain.OneTimeTokenRepository
+	apiKeys     domain.APIKeyRepository
+	invitations domain.InvitationRepository
+	codes       domain.AuthorizationCodeRepository
+	throttle    domain.LoginThrottle
+	reports     domain.RetentionReportRepository
+	period      time.Duration
+	interval    time.Duration
+	audit       domain.AuditLogger
+}
+
+// NewRetentionService period süresinden uzun süredir silinmiş kullanıcıları interval aralıklarla temizler.
+// period sıfırsa zamanlanmış temizlik kapalıdır; restore/purge uçları yine çalışır.
+func NewRetentionService(users domain.UserRepository, sessions domain.SessionRepository, tokens domain.OneTimeTokenRepository, apiKeys domain.APIKeyRepository, invitations domain.InvitationRepository, codes domain.AuthorizationCodeRepository, throttle domain.LoginThrottle, reports domain.RetentionReportRepository, period, interval time.Duration, audit domain.AuditLogger) domain.RetentionService {
+	return &retentionService{
+		users:       users,
+		sessions:    sessions,
+		tokens:      tokens,
+		apiKeys:     apiKeys,
+		invitations: invitations,
+		codes:       codes,
+		throttle:    throttle,
+		reports:     reports,
+		period:      period,
+		interval:    interval,
+		audit:       audit,
+	}
+}
+
+func (s *retentionService) Restore(ctx context.Context, id string) error {
+	restored, err := s.users.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return s.notDeletedError(ctx, id)
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserRestore, TargetType: domain.AuditTargetUser, TargetID: id})
+	return nil
+}
+
+func (s *retentionService) Purge(ctx context.Context, id string) error {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	if user.DeletedAt == nil {
+		return domain.ErrUserNotDeleted
+	}
+	if err := s.purge(ctx, user); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserPurge, TargetType: domain.AuditTargetUser, TargetID: id})
+	return nil
+}
+
+func (s *retentionService) Run(ctx context.Context, trigger string) (*domain.RetentionReport, error) {
+	report := &domain.RetentionReport{
+		Trigger:   trigger,
+		Cutoff:    time.Now().Add(-s.period),
+		Purged:    []domain.PurgedUser{},
+		StartedAt: time.Now(),
+	}
+
+	runErr := s.purgeExpired(ctx, report)
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+	report.Count = len(report.Purged)
+	report.EndedAt = time.Now()
+
+	// Kısmen başarısız çalıştırmada bile silinenler raporlanır
+	if err := s.reports.Create(ctx, report); err != nil {
+		log.Printf("Retention report save error: %v", err)
+	}
+	log.Printf("Retention run (%s): purged %d users deleted before %s", trigger, report.Count, report.Cutoff.Format(time.RFC3339))
+
+	purgedIDs := make([]string, 0, len(report.Purged))
+	for _, p := range report.Purged {
+		purgedIDs = append(purgedIDs, p.ID)
+	}
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditRetentionRun,
+		TargetType: domain.AuditTargetRetention,
+		TargetID:   report.ID.Hex(),
+		Metadata:   map[string]interface{}{"trigger": trigger, "cutoff": report.Cutoff, "purged_user_ids": purgedIDs, "error": report.Error},
+	})
+	return report, runErr
+}
+
+func (s *retentionService) Reports(ctx context.Context, limit int64) ([]*domain.RetentionReport, error) {
+	return s.reports.List(ctx, limit)
+}
+
+func (s *retentionService) Start(ctx context.Context) {
+	if s.period <= 0 || s.interval <= 0 {
+		log.Printf("User retention job disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Run(ctx, domain.RetentionTriggerSchedule); err != nil {
+					log.Printf("Retention run error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (s *retentionService) purgeExpired(ctx context.Context, report *domain.RetentionReport) error {
+	for {
+		users, err := s.users.ListDeletedBefore(ctx, report.Cutoff, retentionBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if err := s.purge(ctx, user); err != nil {
+				if errors.Is(err, domain.ErrUserNotDeleted) {
+					continue
+				}
+				return err
+			}
+			report.Purged = append(report.Purged, domain.PurgedUser{ID: user.ID.Hex(), Email: user.Email, DeletedAt: *user.DeletedAt})
+		}
+		if len(users) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// purge önce kullanıcıya bağlı kayıtları siler; yarıda kalırsa kullanıcı bir sonraki çalıştırmada tekrar denenir.
+// Audit ve KVKK silme kayıtları yasal kayıt oldukları için silinmez.
+func (s *retentionService) purge(ctx context.Context, user *domain.User) error {
+	userID := user.ID.Hex()
+	if err := s.sessions.DeleteUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.tokens.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.apiKeys.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.invitations.DeleteByUser(ctx, userID, user.Email); err != nil {
+		return err
+	}
+	if err := s.codes.DeleteByUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.throttle.Forget(ctx, user.Email); err != nil {
+		return err
+	}
+
+	purged, err := s.users.Purge(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		// Bu arada geri alınmış olabilir
+		return domain.ErrUserNotDeleted
+	}
+	return nil
+}
+
+func (s *retentionService) notDeletedError(ctx context.Context, id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return domain.ErrUserNotFound
+	}
+	if _, err := s.users.GetByID(ctx, id); err != nil {
+		return domain.ErrUserNotFound
+	}
+	return domain.ErrUserNotDeleted
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+type roleService struct {
+	repo  domain.RoleRepository
+	users domain.UserRepository
+	audit domain.AuditLogger
+}
+
+func NewRoleService(repo domain.RoleRepository, users domain.UserRepository, audit domain.AuditLogger) domain.RoleService {
+	return &roleService{
+		repo:  repo,
+		users: users,
+		audit: audit,
+	}
+}
+
+func (s *roleService) List(ctx context.Context) ([]*domain.Role, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *roleService) Get(ctx context.Context, name string) (*domain.Role, error) {
+	return s.repo.Get(ctx, name)
+}
+
+func (s *roleService) Create(ctx context.Context, role *domain.Role) error {
+	if !roleNamePattern.MatchString(role.Name) {
+		return domain.ErrInvalidRoleName
+	}
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+	role.System = false // Sistem rolleri sadece EnsureDefaults ile oluşur
+	if err := s.repo.Create(ctx, role); err != nil {
+		return err
+	}
+
+	_, after := auditDiff(nil, role)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditRoleCreate, TargetType: domain.AuditTargetRole, TargetID: role.Name, After: after})
+	return nil
+}
+
+func (s *roleService) Update(ctx context.Context, name string, role *domain.Role) error {
+	existing, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := validatePermissions(role.Permissions); err != nil {
+		return err
+	}
+	// Admin rolünün yetkileri daraltılırsa kimse rolleri geri düzeltemez
+	if existing.Name == domain.RoleAdmin && !slices.Contains(role.Permissions, domain.PermissionAll) {
+		return errors.New("admin role must keep all permissions")
+	}
+
+	previous := *existing
+	existing.Description = role.Description
+	existing.Permissions = role.Permissions
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return err
+	}
+
+	before, after := auditDiff(&previous, existing)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditRoleUpdate, TargetType: domain.AuditTargetRole, TargetID: name, Before: before, After: after})
+	return nil
+}
+
+func (s *roleService) Delete(ctx context.Context, name string) error {
+	existing, err := s.repo.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing.System {
+		return domain.ErrSystemRole
+	}
+
+	count, err := s.users.CountByRole(ctx, name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrRoleInUse
+	}
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	before, _ := auditDiff(existing, nil)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditRoleDelete, TargetType: domain.AuditTargetRole, TargetID: name, Before: before})
+	return nil
+}
+
+func (s *roleService) Permissions(ctx context.Context, roleName string) ([]string, error) {
+	role, err := s.repo.Get(ctx, roleName)
+	if errors.Is(err, domain.ErrRoleNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return role.Permissions, nil
+}
+
+// validatePermissions bilinen yetkileri ve "*", "kaynak:*" jokerlerini kabul eder
+func validatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if p == domain.PermissionAll || slices.Contains(domain.Permissions, p) {
+			continue
+		}
+		if resource, action, ok := strings.Cut(p, ":"); ok && action == "*" && knownResource(resource) {
+			continue
+		}
+		return fmt.Errorf("%w: %s", domain.ErrUnknownPermission, p)
+	}
+	return nil
+}
+
+func knownResource(resource string) bool {
+	for _, p := range domain.Permissions {
+		if r, _, _ := strings.Cut(p, ":"); r == resource {
+			return true
+		}
+	}
+	return false
+}
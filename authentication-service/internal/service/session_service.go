@@ -0,0 +1,92 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"time"
+)
+
+type sessionService struct {
+	sessions   domain.SessionRepository
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	audit      domain.AuditLogger
+}
+
+// NewSessionService accessTTL iptal akışının ne kadar geriye gideceğini belirler:
+// daha önce iptal edilen oturumların access token'ları zaten süresi dolmuştur.
+// refreshTTL boyunca refresh edilmeyen oturumlar listede gösterilmez.
+func NewSessionService(sessions domain.SessionRepository, accessTTL, refreshTTL time.Duration, audit domain.AuditLogger) domain.SessionService {
+	return &sessionService{
+		sessions:   sessions,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		audit:      audit,
+	}
+}
+
+func (s *sessionService) List(ctx context.Context, userID, currentSessionID string) ([]*domain.Session, error) {
+	sessions, err := s.sessions.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Refresh token'ı süresi dolmuş oturum iptal edilmemiş olsa da kullanılamaz
+	active := make([]*domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		lastSeen := session.LastSeenAt
+		if session.CreatedAt.After(lastSeen) {
+			lastSeen = session.CreatedAt
+		}
+		if time.Since(lastSeen) > s.refreshTTL {
+			continue
+		}
+		session.Current = session.ID.Hex() == currentSessionID
+		active = append(active, session)
+	}
+	return active, nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	// Başka kullanıcının oturumunun varlığı da belli edilmez
+	if err != nil || session.UserID != userID || session.RevokedAt != nil {
+		return domain.ErrSessionNotFound
+	}
+	if err := s.sessions.RevokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditSessionRevoke,
+		TargetType: domain.AuditTargetSession,
+		TargetID:   sessionID,
+		Metadata:   map[string]interface{}{"user_id": userID, "user_agent": session.UserAgent, "ip": session.IP},
+	})
+	return nil
+}
+
+func (s *sessionService) RevokeAll(ctx context.Context, userID string) error {
+	if err := s.sessions.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditSessionRevokeAll, TargetType: domain.AuditTargetUser, TargetID: userID})
+	return nil
+}
+
+func (s *sessionService) RevokedSince(ctx context.Context, since time.Time) (*domain.RevokedSessionFeed, error) {
+	until := time.Now()
+	if oldest := until.Add(-s.accessTTL); since.Before(oldest) {
+		since = oldest
+	}
+
+	sessions, err := s.sessions.ListRevokedSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &domain.RevokedSessionFeed{Sessions: make([]domain.RevokedSession, 0, len(sessions)), Until: until}
+	for _, session := range sessions {
+		feed.Sessions = append(feed.Sessions, domain.RevokedSession{SessionID: session.ID.Hex(), RevokedAt: *session.RevokedAt})
+	}
+	return feed, nil
+}
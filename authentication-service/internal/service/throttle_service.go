@@ -0,0 +1,150 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// ThrottleConfig başarısız giriş denemesi limitleri.
+// FreeAttempts'ten sonraki her hata BackoffBase*2^n kadar bekleme getirir (BackoffMax ile sınırlı),
+// MaxAttempts'e ulaşılınca anahtar LockoutDuration boyunca kilitlenir.
+type ThrottleConfig struct {
+	FreeAttempts    int
+	MaxAttempts     int
+	IPFreeAttempts  int
+	IPMaxAttempts   int
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+	LockoutDuration time.Duration
+	FailureWindow   time.Duration // Bu süre boyunca hata olmazsa sayaç sıfırlanır
+}
+
+type loginThrottle struct {
+	repo domain.LoginThrottleRepository
+	cfg  ThrottleConfig
+}
+
+func NewLoginThrottle(repo domain.LoginThrottleRepository, cfg ThrottleConfig) domain.LoginThrottle {
+	return &loginThrottle{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+func (t *loginThrottle) Check(ctx context.Context, email, ip string) error {
+	for _, key := range []string{accountKey(email), ipKey(ip)} {
+		if key == "" {
+			continue
+		}
+		record, err := t.repo.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if record != nil && record.LockedUntil != nil {
+			if wait := time.Until(*record.LockedUntil); wait > 0 {
+				return &domain.LockoutError{RetryAfter: wait}
+			}
+		}
+	}
+	return nil
+}
+
+func (t *loginThrottle) RegisterFailure(ctx context.Context, email, ip string) error {
+	if err := t.registerFailure(ctx, accountKey(email), t.cfg.FreeAttempts, t.cfg.MaxAttempts, &domain.LockoutEvent{
+		Type:  domain.LockoutEventAccountLocked,
+		Email: normalizeEmail(email),
+		IP:    ip,
+	}); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return t.registerFailure(ctx, ipKey(ip), t.cfg.IPFreeAttempts, t.cfg.IPMaxAttempts, &domain.LockoutEvent{
+		Type: domain.LockoutEventIPLocked,
+		IP:   ip,
+	})
+}
+
+// RegisterSuccess sadece hesap sayacını sıfırlar; IP sayacı, saldırganın kendi hesabıyla
+// giriş yaparak sayacı sıfırlamasını önlemek için kendi süresiyle düşer.
+func (t *loginThrottle) RegisterSuccess(ctx context.Context, email string) error {
+	return t.repo.Reset(ctx, accountKey(email))
+}
+
+func (t *loginThrottle) Unlock(ctx context.Context, email, actorID string) error {
+	if err := t.repo.Reset(ctx, accountKey(email)); err != nil {
+		return err
+	}
+	return t.repo.RecordEvent(ctx, &domain.LockoutEvent{
+		Type:    domain.LockoutEventUnlocked,
+		Email:   normalizeEmail(email),
+		ActorID: actorID,
+	})
+}
+
+func (t *loginThrottle) Forget(ctx context.Context, email string) error {
+	if err := t.repo.Reset(ctx, accountKey(email)); err != nil {
+		return err
+	}
+	return t.repo.DeleteEvents(ctx, normalizeEmail(email))
+}
+
+func (t *loginThrottle) registerFailure(ctx context.Context, key string, free, max int, event *domain.LockoutEvent) error {
+	record, err := t.repo.RegisterFailure(ctx, key, t.cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if record.Failures <= free {
+		return nil
+	}
+
+	var wait time.Duration
+	if record.Failures >= max {
+		wait = t.cfg.LockoutDuration
+	} else {
+		wait = t.backoff(record.Failures - free)
+	}
+	until := time.Now().Add(wait)
+	if err := t.repo.SetLockedUntil(ctx, key, until); err != nil {
+		return err
+	}
+
+	// Kilitlenme olayını sadece eşiğin aşıldığı anda kaydet
+	if record.Failures == max {
+		event.Failures = record.Failures
+		event.LockedUntil = &until
+		if err := t.repo.RecordEvent(ctx, event); err != nil {
+			log.Printf("Lockout event record error: %v", err)
+		}
+		log.Printf("Login locked for %s until %s", key, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// backoff n. fazla denemede beklenecek süre: base, 2*base, 4*base ... (en fazla BackoffMax)
+func (t *loginThrottle) backoff(n int) time.Duration {
+	wait := t.cfg.BackoffBase
+	for i := 1; i < n && wait < t.cfg.BackoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, t.cfg.BackoffMax)
+}
+
+func accountKey(email string) string {
+	return "account:" + normalizeEmail(email)
+}
+
+func ipKey(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	return "ip:" + ip
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -4,17 +4,44 @@ import (
 	"authentication-service/internal/domain"
 	"context"
 	"errors"
+	"log"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// Listeleme sadece bu alanlara göre sıralanabilir
+var sortableUserFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"deleted_at": true,
+	"email":      true,
+	"first_name": true,
+	"last_name":  true,
+}
+
 type userService struct {
-	repo domain.UserRepository
+	repo         domain.UserRepository
+	roles        domain.RoleRepository
+	throttle     domain.LoginThrottle
+	geocoder     domain.Geocoder
+	verification domain.VerificationService
+	audit        domain.AuditLogger
 }
 
-func NewUserService(repo domain.UserRepository) domain.UserService {
+func NewUserService(repo domain.UserRepository, roles domain.RoleRepository, throttle domain.LoginThrottle, geocoder domain.Geocoder, verification domain.VerificationService, audit domain.AuditLogger) domain.UserService {
 	return &userService{
-		repo: repo,
+		repo:         repo,
+		roles:        roles,
+		throttle:     throttle,
+		geocoder:     geocoder,
+		verification: verification,
+		audit:        audit,
 	}
 }
 
@@ -22,19 +49,37 @@ func (s *userService) Get(ctx context.Context, id string) (*domain.User, error)
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *userService) GetAll(ctx context.Context) ([]*domain.User, error) {
-	return s.repo.GetAll(ctx)
+func (s *userService) List(ctx context.Context, filter domain.UserFilter) (*domain.UserPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit > maxPageLimit {
+		filter.Limit = maxPageLimit
+	}
+	if !sortableUserFields[filter.Sort] {
+		filter.Sort = "created_at"
+		filter.Desc = true
+	}
+
+	users, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.UserPage{Items: users, Total: total, Page: filter.Page, Limit: filter.Limit}, nil
 }
 
 func (s *userService) Create(ctx context.Context, user *domain.User) error {
-	// Admin manuel ekleme yaparken de adres zorunlu
+	// Davetle açılan hesaplarda da adres zorunlu
 	if len(user.Addresses) == 0 {
 		return errors.New("at least one address is required")
 	}
 
 	existing, _ := s.repo.GetByEmail(ctx, user.Email)
 	if existing != nil {
-		return errors.New("email already exists")
+		return domain.ErrEmailInUse
 	}
 
 	if user.Password != "" {
@@ -46,44 +91,154 @@ func (s *userService) Create(ctx context.Context, user *domain.User) error {
 	}
 
 	user.Active = true
+	user.OrgID = domain.UserOrganization(user.OrgID)
+	user.Addresses = domain.PrepareAddresses(user.Addresses)
+	geocodeAddresses(ctx, s.geocoder, user.Addresses, nil)
 	if user.Role == "" {
-		user.Role = "user" // Default rol
+		user.Role = domain.RoleUser // Default rol
+	}
+	if _, err := s.roles.Get(ctx, user.Role); err != nil {
+		return err
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return err
 	}
-	return s.repo.Create(ctx, user)
+
+	_, after := auditDiff(nil, user)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserCreate, TargetType: domain.AuditTargetUser, TargetID: user.ID.Hex(), After: after})
+	return nil
 }
 
-func (s *userService) Update(ctx context.Context, id string, user *domain.User) error {
+func (s *userService) Update(ctx context.Context, id string, update domain.UserUpdate) error {
 	existing, err := s.repo.GetByID(ctx, id)
-	if err != nil {
-		return err
+	// Silinmiş kullanıcı güncellenemez; aksi halde active=true ile geri açılıp saklama süresi
+	// sonunda canlı hesap olarak kalıcı silinirdi. Geri almak için Restore kullanılır.
+	if err != nil || existing.DeletedAt != nil {
+		return domain.ErrUserNotFound
 	}
+	previous := *existing
 
-	existing.FirstName = user.FirstName
-	existing.LastName = user.LastName
-	existing.Email = user.Email
-	existing.Active = user.Active
-	existing.Role = user.Role // Role'ü güncelle
+	// Adresler sadece /users/{id}/addresses uçlarından değişir
+	fields := []string{}
+	if update.FirstName != nil {
+		existing.FirstName = *update.FirstName
+		fields = append(fields, "first_name")
+	}
+	if update.LastName != nil {
+		existing.LastName = *update.LastName
+		fields = append(fields, "last_name")
+	}
+	emailChanged := false
+	if update.Email != nil {
+		email := strings.TrimSpace(*update.Email)
+		if email == "" {
+			return domain.ErrEmailRequired
+		}
+		if !strings.EqualFold(email, existing.Email) {
+			// Yeni adres doğrulanana kadar hesap doğrulanmamış sayılır
+			existing.VerificationPending = true
+			existing.EmailVerifiedAt = nil
+			fields = append(fields, "verification_pending", "email_verified_at")
+			emailChanged = true
+		}
+		existing.Email = email
+		fields = append(fields, "email")
+	}
+	if update.Active != nil {
+		existing.Active = *update.Active
+		fields = append(fields, "active")
+	}
+	// Boş rol mevcut rolü korur; tanımsız bir rol atanamaz
+	if update.Role != nil && *update.Role != "" && *update.Role != existing.Role {
+		if _, err := s.roles.Get(ctx, *update.Role); err != nil {
+			return err
+		}
+		existing.Role = *update.Role
+		fields = append(fields, "role")
+	}
 
-	// Eğer güncelleme isteğinde adres gönderilmişse güncelle, boşsa eskisini koru veya hata fırlat
-	// Senaryo: Adres listesi tamamen değiştirilmek isteniyor olabilir.
-	if len(user.Addresses) > 0 {
-		existing.Addresses = user.Addresses
+	if len(fields) == 0 {
+		return nil
 	}
-	// Not: Eğer kullanıcı adreslerini tamamen silmeye çalışırsa (boş array yollarsa)
-	// yukarıdaki if bloğu çalışmaz ve eski adresler kalır.
-	// Eğer silmeye izin verilmeyecekse bu mantık doğrudur (En az 1 adres kuralı).
+	if err := s.repo.Update(ctx, existing, fields...); err != nil {
+		return err
+	}
+	s.recordUpdate(ctx, &previous, existing)
 
-	if user.Password != "" {
-		hashed, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
-		if err != nil {
-			return err
+	// Mail gönderilemese de değişiklik geçerli; kullanıcı /auth/verify/resend ile tekrar isteyebilir
+	if emailChanged {
+		if err := s.verification.SendVerification(ctx, existing); err != nil {
+			log.Printf("Verification mail error for %s: %v", existing.Email, err)
 		}
-		existing.Password = string(hashed)
 	}
+	return nil
+}
 
-	return s.repo.Update(ctx, existing)
+// recordUpdate değişen profil alanlarını kaydeder
+func (s *userService) recordUpdate(ctx context.Context, previous, updated *domain.User) {
+	before, after := auditDiff(previous, updated)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserUpdate, TargetType: domain.AuditTargetUser, TargetID: updated.ID.Hex(), Before: before, After: after})
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, id string, profile *domain.User) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil || existing.DeletedAt != nil {
+		return domain.ErrUserNotFound
+	}
+	previous := *existing
+
+	// Sadece profil alanları; yetki, e-posta, şifre ve adresler kendi akışlarından değişir
+	existing.FirstName = profile.FirstName
+	existing.LastName = profile.LastName
+
+	if err := s.repo.Update(ctx, existing, "first_name", "last_name"); err != nil {
+		return err
+	}
+	s.recordUpdate(ctx, &previous, existing)
+	return nil
 }
 
 func (s *userService) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserDelete, TargetType: domain.AuditTargetUser, TargetID: id})
+	return nil
+}
+
+func (s *userService) ChangeRole(ctx context.Context, id, role string) error {
+	if _, err := s.roles.Get(ctx, role); err != nil {
+		return err
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil || user.DeletedAt != nil {
+		return domain.ErrUserNotFound
+	}
+	previous := user.Role
+	user.Role = role
+	if err := s.repo.Update(ctx, user, "role"); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditUserRoleChange,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   id,
+		Before:     map[string]interface{}{"role": previous},
+		After:      map[string]interface{}{"role": role},
+	})
+	return nil
+}
+
+func (s *userService) Unlock(ctx context.Context, id, actorID string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.throttle.Unlock(ctx, user.Email, actorID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditUserUnlock, TargetType: domain.AuditTargetUser, TargetID: id})
+	return nil
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const tokenTypeEmailVerification = "email_verification"
+
+type verificationService struct {
+	repo       domain.UserRepository
+	keys       domain.KeyService
+	mailer     domain.Mailer
+	appBaseURL string
+	ttl        time.Duration
+}
+
+// NewVerificationService doğrulama linkleri veritabanında tutulmaz; token imzalı bir JWT'dir
+// ve içindeki e-posta adresi kullanıcının güncel adresiyle eşleşmelidir.
+func NewVerificationService(repo domain.UserRepository, keys domain.KeyService, mailer domain.Mailer, appBaseURL string, ttl time.Duration) domain.VerificationService {
+	return &verificationService{
+		repo:       repo,
+		keys:       keys,
+		mailer:     mailer,
+		appBaseURL: strings.TrimRight(appBaseURL, "/"),
+		ttl:        ttl,
+	}
+}
+
+func (s *verificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	now := time.Now()
+	token, err := s.keys.Sign(jwt.MapClaims{
+		"typ":   tokenTypeEmailVerification,
+		"sub":   user.ID.Hex(),
+		"email": user.Email,
+		"iat":   now.Unix(),
+		"exp":   now.Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.appBaseURL, url.QueryEscape(token))
+	return s.mailer.Send(ctx, domain.MailMessage{
+		To:      user.Email,
+		Subject: "E-posta adresinizi doğrulayın",
+		Body: fmt.Sprintf("Merhaba %s,\n\nHesabınızı etkinleştirmek için e-posta adresinizi doğrulayın:\n%s\n\n"+
+			"Link %d saat boyunca geçerlidir.\n",
+			user.FirstName, link, int(s.ttl.Hours())),
+	})
+}
+
+func (s *verificationService) Verify(ctx context.Context, token string) error {
+	parsed, err := jwt.Parse(token, s.keys.Keyfunc, jwt.WithValidMethods(s.keys.ValidMethods()), jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return domain.ErrInvalidOneTimeToken
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != tokenTypeEmailVerification {
+		return domain.ErrInvalidOneTimeToken
+	}
+	userID, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || user.DeletedAt != nil {
+		return domain.ErrInvalidOneTimeToken
+	}
+	// Link gönderildikten sonra e-posta değiştiyse eski link geçersizdir
+	if !strings.EqualFold(user.Email, email) {
+		return domain.ErrInvalidOneTimeToken
+	}
+	if !user.VerificationPending {
+		return nil // Zaten doğrulanmış, link tekrar tıklanmış olabilir
+	}
+
+	now := time.Now()
+	user.VerificationPending = false
+	user.EmailVerifiedAt = &now
+	return s.repo.Update(ctx, user, "verification_pending", "email_verified_at")
+}
+
+// Resend hesap yoksa veya zaten doğrulanmışsa sessizce hiçbir şey yapmaz
+func (s *verificationService) Resend(ctx context.Context, email string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil || !user.VerificationPending || user.DeletedAt != nil {
+		return nil
+	}
+	return s.SendVerification(ctx, user)
+}
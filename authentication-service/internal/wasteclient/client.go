@@ -0,0 +1,108 @@
+package wasteclient
+
+import (
+	"authentication-service/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Export ZIP'i resimleri de içerdiği için boyut sınırı geniş tutulur
+const maxExportSize = 512 << 20
+
+type client struct {
+	baseURL      string
+	http         *http.Client
+	oidc         domain.OIDCService
+	clientID     string
+	clientSecret string
+}
+
+// NewClient waste servisinin /api/privacy uçlarına istek atar. Her istek için servis
+// istemcisinin kimliğiyle, sadece o işlemin scope'unu taşıyan client_credentials token'ı alınır.
+func NewClient(baseURL string, timeout time.Duration, oidc domain.OIDCService, clientID, clientSecret string) domain.WasteClient {
+	return &client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		http:         &http.Client{Timeout: timeout},
+		oidc:         oidc,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (c *client) Export(ctx context.Context, userID string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/privacy/export", domain.ScopePrivacyExport, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxExportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrWasteServiceUnavailable, err)
+	}
+	if len(data) > maxExportSize {
+		return nil, fmt.Errorf("waste export exceeds %d bytes", maxExportSize)
+	}
+	return data, nil
+}
+
+func (c *client) Erase(ctx context.Context, userID string) (*domain.WasteErasure, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/api/privacy/erase", domain.ScopePrivacyErase, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result domain.WasteErasure
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrWasteServiceUnavailable, err)
+	}
+	return &result, nil
+}
+
+func (c *client) do(ctx context.Context, method, path, scope, userID string) (*http.Response, error) {
+	token, err := c.serviceToken(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"user_id": {userID}}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrWasteServiceUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s %s returned %d: %s", domain.ErrWasteServiceUnavailable, method, path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (c *client) serviceToken(ctx context.Context, scope string) (string, error) {
+	if c.clientSecret == "" {
+		return "", fmt.Errorf("%w: service client is not configured", domain.ErrWasteServiceUnavailable)
+	}
+	resp, err := c.oidc.Token(ctx, domain.TokenRequest{
+		GrantType:    domain.GrantClientCredentials,
+		Scope:        scope,
+		ClientID:     c.clientID,
+		ClientSecret: c.clientSecret,
+	}, domain.ClientInfo{})
+	if err != nil {
+		return "", fmt.Errorf("%w: service token: %v", domain.ErrWasteServiceUnavailable, err)
+	}
+	return resp.AccessToken, nil
+}
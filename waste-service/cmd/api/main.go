@@ -6,6 +6,8 @@ import (
 	"net/http" // <--- 1. Bunu ekledim (Method sabitleri için)
 	"time"
 	"waste-service/internal/config"
+	"waste-service/internal/domain"
+	"waste-service/internal/fixtures"
 	handler "waste-service/internal/handler/http"
 	"waste-service/internal/middleware"
 	"waste-service/internal/repository"
@@ -31,20 +33,37 @@ func main() {
 	}
 
 	db := client.Database(cfg.DbName)
+	auditDb := client.Database(cfg.AuditDbName)
+
+	// Organizasyon öncesi kayıtlar varsayılan organizasyona taşınır; sorgular org_id'siz kayıtları göremez
+	if err := repository.BackfillOrganization(ctx, db, domain.DefaultOrganizationID); err != nil {
+		log.Fatalf("Organizasyon alanı doldurulamadı: %v", err)
+	}
 
 	// 3. Katmanları Başlat (Dependency Injection)
+	auditLogger := service.NewAuditLogger(repository.NewMongoAuditRepository(auditDb))
 	repo := repository.NewMongoRepository(db)
-	svc := service.NewWasteService(repo, cfg.AIServiceURL)
+	svc := service.NewWasteService(repo, cfg.AIServiceURL, auditLogger)
 	h := handler.NewWasteHandler(svc)
+	privacyHandler := handler.NewPrivacyHandler(service.NewPrivacyService(repository.NewMongoPrivacyRepository(db), auditLogger))
 
-	// Demo veri ekle (Harita boş gelmesin)
-	repo.SeedPoints(context.Background())
-	repo.SeedWastes(context.Background())
+	// Demo veri (harita boş gelmesin) sadece açıkça istenirse ve production dışında yüklenir
+	if cfg.FixturesEnabled {
+		file, err := fixtures.Load(cfg.FixturesFile)
+		if err != nil {
+			log.Fatalf("Fixture dosyası okunamadı: %v", err)
+		}
+		fixtureCtx := domain.WithTenant(context.Background(), domain.Tenant{OrgID: domain.DefaultOrganizationID})
+		if err := fixtures.Apply(fixtureCtx, cfg.AppEnv, repo, file); err != nil {
+			log.Fatalf("Fixture yüklenemedi: %v", err)
+		}
+	}
 
 	// Debug: Etki analizi sonuçlarını kontrol et
 	go func() {
 		time.Sleep(2 * time.Second)
-		impact, err := svc.GetImpactAnalysis(context.Background())
+		allOrgs := domain.WithTenant(context.Background(), domain.Tenant{OrgID: domain.DefaultOrganizationID, AllOrganizations: true})
+		impact, err := svc.GetImpactAnalysis(allOrgs)
 		if err != nil {
 			log.Printf("❌ Etki analizi hatası: %v", err)
 		} else {
@@ -56,8 +75,34 @@ func main() {
 		}
 	}()
 
+	// Token doğrulama: auth servisinin public key'leri ile lokal, gerekirse remote
+	keySet, err := middleware.NewKeySet(cfg.AuthJWKSURL, cfg.AuthPublicKeyFile, cfg.AuthValidateTimeout)
+	if err != nil {
+		log.Fatalf("Auth public key yüklenemedi: %v", err)
+	}
+	keySet.Start(context.Background(), cfg.JWKSRefreshInterval)
+
+	// Auth servisine introspection ve iptal akışı için servis kimliğiyle gidilir. Kimlik olmadan
+	// iptal edilen oturumlar takip edilemez ve lokal doğrulanan token'ları kabul edilmeye devam eder.
+	if cfg.AuthClientSecret == "" {
+		log.Fatalf("AUTH_CLIENT_SECRET tanımlı değil: iptal edilen oturumlar takip edilemeden servis açılamaz")
+	}
+	credentials := middleware.NewClientCredentials(cfg.AuthServiceURL, cfg.AuthClientID, cfg.AuthClientSecret, cfg.AuthValidateTimeout)
+
+	breaker := middleware.NewCircuitBreaker(cfg.AuthBreakerFailures, cfg.AuthBreakerCooldown)
+	remote := middleware.NewRemoteValidator(cfg.AuthServiceURL, credentials, cfg.AuthValidateTimeout, cfg.AuthCacheTTL, breaker)
+	revocations := middleware.NewRevocationList(cfg.AuthServiceURL, credentials, cfg.AuthValidateTimeout, cfg.RevokedSessionTTL)
+	revocations.Start(context.Background(), cfg.SessionRevocationInterval)
+	verifier := middleware.NewTokenVerifier(keySet, remote, revocations)
+
 	// 4. Echo Server
 	e := echo.New()
+	// Audit kayıtlarındaki IP; başlıklara sadece güvenilen proxy'lerden inanılır
+	ipExtractor, err := middleware.IPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("TRUSTED_PROXIES geçersiz: %v", err)
+	}
+	e.IPExtractor = ipExtractor
 
 	// ==========================================
 	// 2. CORS AYARLARI BURAYA (DİĞERİYLE AYNI)
@@ -65,7 +110,7 @@ func main() {
 	e.Use(echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
 		AllowOrigins: []string{"*"},
 		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, middleware.HeaderAPIKey},
 	}))
 
 	e.Use(echoMiddleware.Logger())
@@ -75,7 +120,8 @@ func main() {
 	e.Static("/uploads", "./uploads")
 
 	// 5. Public Rotalar (Auth gerektirmeyen)
-	publicGroup := e.Group("/api")
+	// Organizasyon ?org= ile seçilir, verilmezse varsayılan organizasyon kullanılır
+	publicGroup := e.Group("/api", middleware.PublicTenant())
 	publicGroup.GET("/impact-analysis", func(c echo.Context) error {
 		return h.GetImpactAnalysis(c)
 	})
@@ -89,12 +135,14 @@ func main() {
 	// 6. Korumalı Rotalar (Auth gerektiren)
 	// Tüm /api rotaları Auth korumasında olacak
 	apiGroup := e.Group("/api")
-	apiGroup.Use(middleware.AuthGuard(cfg.AuthServiceURL))
+	apiGroup.Use(middleware.AuthGuard(verifier))
 
 	// Harita noktaları için login gerekli
 	apiGroup.GET("/points", h.GetPoints)
 
 	h.RegisterRoutes(apiGroup)
+	// KVKK/GDPR: auth servisinin dışa aktarma ve silme istekleri; kullanıcı token'larıyla çağrılamaz
+	privacyHandler.RegisterRoutes(e.Group("/api"), verifier)
 
 	// 7. Başlat
 	log.Printf("Waste Service running on port %s", cfg.Port)
@@ -3,8 +3,10 @@ module waste-service
 go 1.24.2
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/labstack/echo/v4 v4.14.0
 	go.mongodb.org/mongo-driver v1.17.6
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
@@ -1,22 +1,78 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	Port           string
 	MongoURI       string
 	DbName         string
+	AuditDbName    string // Auth servisiyle ortak denetim kaydı veritabanı
 	AuthServiceURL string // Auth servisine istek atmak için
 	AIServiceURL   string // AI servisine istek atmak için
+
+	AppEnv          string // development, staging, production
+	FixturesEnabled bool   // Demo verileri yükle (production'da her zaman reddedilir)
+	FixturesFile    string
+
+	// X-Forwarded-For'una güvenilen reverse proxy ağları (CIDR). Boşsa istemci IP'si bağlantıdan alınır.
+	TrustedProxies []string
+
+	// Token'ların lokal doğrulanması
+	AuthJWKSURL         string        // Auth servisinin public key'leri (JWKS)
+	AuthPublicKeyFile   string        // Opsiyonel: elle verilen PEM public key
+	JWKSRefreshInterval time.Duration // JWKS'nin arka planda yenilenme sıklığı
+
+	// Lokal doğrulama yapılamadığında auth servisine sorma (fallback)
+	AuthValidateTimeout time.Duration
+	AuthCacheTTL        time.Duration // Başarılı doğrulama sonucunun cache süresi
+	AuthBreakerFailures int           // Devre kesicinin açılması için art arda hata sayısı
+	AuthBreakerCooldown time.Duration // Devre açıkken auth servisine gidilmeyecek süre
+
+	// Servisin auth'taki client_credentials kimliği (auth'taki SERVICE_CLIENT_ID/SECRET ile aynı); secret zorunludur
+	AuthClientID     string
+	AuthClientSecret string
+
+	// İptal edilen oturumların auth servisinden takibi
+	SessionRevocationInterval time.Duration // İptal akışının çekilme sıklığı
+	RevokedSessionTTL         time.Duration // İptal kaydının tutulacağı süre (auth'taki ACCESS_TOKEN_TTL'den uzun olmalı)
 }
 
 func LoadConfig() *Config {
+	authServiceURL := strings.TrimRight(getEnv("AUTH_SERVICE_URL", "http://localhost:8080"), "/")
+
 	return &Config{
 		Port:           getEnv("PORT", "8081"), // Auth 8080 ise bu 8081 olsun
 		MongoURI:       getEnv("MONGO_URI", "mongodb+srv://tesodevmongodb:<db_password>@cluster0.ajddxq7.mongodb.net/?appName=Cluster0"),
 		DbName:         getEnv("DB_NAME", "waste_db"),
-		AuthServiceURL: getEnv("AUTH_SERVICE_URL", "http://localhost:8080"),
+		AuditDbName:    getEnv("AUDIT_DB_NAME", "audit_db"),
+		AuthServiceURL: authServiceURL,
 		AIServiceURL:   getEnv("AI_SERVICE_URL", "http://localhost:3000/risk-degree"),
+
+		AppEnv:          getEnv("APP_ENV", "development"),
+		FixturesEnabled: getBool("FIXTURES_ENABLED", false),
+		FixturesFile:    getEnv("FIXTURES_FILE", "./fixtures/waste.yaml"),
+
+		TrustedProxies: getList("TRUSTED_PROXIES", nil),
+
+		AuthJWKSURL:         getEnv("AUTH_JWKS_URL", authServiceURL+"/.well-known/jwks.json"),
+		AuthPublicKeyFile:   getEnv("AUTH_PUBLIC_KEY_FILE", ""),
+		JWKSRefreshInterval: getDuration("JWKS_REFRESH_INTERVAL", 5*time.Minute),
+
+		AuthValidateTimeout: getDuration("AUTH_VALIDATE_TIMEOUT", 2*time.Second),
+		AuthCacheTTL:        getDuration("AUTH_CACHE_TTL", 30*time.Second),
+		AuthBreakerFailures: getInt("AUTH_BREAKER_FAILURES", 5),
+		AuthBreakerCooldown: getDuration("AUTH_BREAKER_COOLDOWN", 30*time.Second),
+
+		AuthClientID:     getEnv("AUTH_CLIENT_ID", "waste-service"),
+		AuthClientSecret: getEnv("AUTH_CLIENT_SECRET", ""),
+
+		SessionRevocationInterval: getDuration("SESSION_REVOCATION_INTERVAL", 15*time.Second),
+		RevokedSessionTTL:         getDuration("REVOKED_SESSION_TTL", time.Hour),
 	}
 }
 
@@ -26,3 +82,45 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return fallback
+}
+
+func getBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
+// getList virgülle ayrılmış değerleri okur ("10.0.0.0/8,172.16.0.0/12")
+func getList(key string, fallback []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Denetim olayları auth servisiyle ortak audit_db.audit_events koleksiyonuna yazılır;
+// sorgulama ve dışa aktarma auth servisinin /admin/audit uçlarından yapılır.
+const (
+	AuditServiceName = "waste-service"
+
+	AuditWasteStatus  = "waste.status_change"
+	AuditWasteDelete  = "waste.delete"
+	AuditPointCreate  = "point.create"
+	AuditPointUpdate  = "point.update"
+	AuditPointDelete  = "point.delete"
+	AuditPrivacyErase = "privacy.erase"
+
+	AuditTargetWaste = "waste"
+	AuditTargetPoint = "point"
+	AuditTargetUser  = "user"
+)
+
+// AuditEvent auth servisindeki kayıtla aynı şemadadır
+type AuditEvent struct {
+	ID                primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	Service           string                 `bson:"service" json:"service"`
+	Action            string                 `bson:"action" json:"action"`
+	ActorID           string                 `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	ActorEmail        string                 `bson:"actor_email,omitempty" json:"actor_email,omitempty"`
+	ImpersonatorID    string                 `bson:"impersonator_id,omitempty" json:"impersonator_id,omitempty"` // Impersonation sırasında actor taklit edilen kullanıcı, bu ise yerine geçen kişidir
+	ImpersonatorEmail string                 `bson:"impersonator_email,omitempty" json:"impersonator_email,omitempty"`
+	OrgID             string                 `bson:"org_id,omitempty" json:"org_id,omitempty"`
+	TargetType        string                 `bson:"target_type,omitempty" json:"target_type,omitempty"`
+	TargetID          string                 `bson:"target_id,omitempty" json:"target_id,omitempty"`
+	Before            map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`
+	After             map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`
+	Metadata          map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	IP                string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent         string                 `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	CreatedAt         time.Time              `bson:"created_at" json:"created_at"`
+}
+
+// AuditRepository sadece ekleme yapar
+type AuditRepository interface {
+	Append(ctx context.Context, event *AuditEvent) error
+}
+
+// AuditLogger olay kaydeder; kayıt hatası asıl işlemi durdurmaz
+type AuditLogger interface {
+	Record(ctx context.Context, event AuditEvent)
+}
+
+// AuditActor isteği yapan kullanıcı ve istemci; AuthGuard request context'ine koyar
+type AuditActor struct {
+	UserID    string
+	Email     string
+	IP        string
+	UserAgent string
+	// Impersonation token'ındaki "act" claim'i
+	ImpersonatorID    string
+	ImpersonatorEmail string
+}
+
+type auditActorKey struct{}
+
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
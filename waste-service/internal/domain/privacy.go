@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ErasedUserID silme talebinden sonra anonimleştirilen kayıtlara yazılan kullanıcı
+const ErasedUserID = "erased"
+
+// PrivacyExport kullanıcının waste servisindeki kişisel verileri (KVKK/GDPR veri taşınabilirliği)
+type PrivacyExport struct {
+	UserID     string               `json:"user_id"`
+	ExportedAt time.Time            `json:"exported_at"`
+	Wastes     []*Waste             `json:"wastes"`
+	Requests   []*CollectionRequest `json:"requests"`
+}
+
+// ErasureResult silme talebinde waste servisinde yapılanların özeti
+type ErasureResult struct {
+	WastesAnonymized   int64 `json:"wastes_anonymized"`
+	RequestsAnonymized int64 `json:"requests_anonymized"`
+	ImagesDeleted      int   `json:"images_deleted"`
+}
+
+type PrivacyRepository interface {
+	GetWastesByUser(ctx context.Context, userID string) ([]*Waste, error)
+	GetRequestsByUser(ctx context.Context, userID string) ([]*CollectionRequest, error)
+	// AnonymizeUser kullanıcının atık ve taleplerini istatistikler korunacak şekilde anonimleştirir
+	AnonymizeUser(ctx context.Context, userID string) (wastes int64, requests int64, err error)
+}
+
+type PrivacyService interface {
+	// Export JSON dosyaları ve yüklenen resimlerden oluşan ZIP'i döner
+	Export(ctx context.Context, userID string) ([]byte, error)
+	// Erase resimleri siler ve kayıtları anonimleştirir
+	Erase(ctx context.Context, userID string) (*ErasureResult, error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	// DefaultOrganizationID organizasyon claim'i taşımayan eski token'lar ve public istekler için
+	DefaultOrganizationID = "default"
+	// RoleSuperAdmin tüm organizasyonların kayıtlarına erişebilen rol (auth servisindeki tanımla aynı)
+	RoleSuperAdmin = "super_admin"
+)
+
+var (
+	// ErrNoTenant repository'ye organizasyonu belirsiz bir context ile gelindiğinde döner;
+	// filtre eksik kalıp tüm organizasyonların verisi okunmasın diye sorgu hiç çalıştırılmaz
+	ErrNoTenant = errors.New("organizasyon bilgisi bulunamadı")
+	ErrNotFound = errors.New("kayıt bulunamadı")
+)
+
+// Tenant sorguların hangi organizasyonla (belediye) sınırlanacağı. AllOrganizations sadece
+// süper admin'ler ve arka plan işleri içindir; yeni kayıtlar yine OrgID'ye yazılır.
+type Tenant struct {
+	OrgID            string
+	AllOrganizations bool
+}
+
+type tenantKey struct{}
+
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
@@ -12,6 +12,7 @@ import (
 type Waste struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID      string             `bson:"user_id" json:"user_id"`
+	OrgID       string             `bson:"org_id" json:"org_id"`         // Kaydın ait olduğu belediye
 	ImagePath   string             `bson:"image_path" json:"image_path"` // Local dosya yolu
 	Description string             `bson:"description" json:"description"`
 	AIAnalysis  *AIAnalysisResult  `bson:"ai_analysis,omitempty" json:"ai_analysis"`
@@ -46,6 +47,7 @@ type AIAnalysisResult struct {
 // Haritadaki noktalar (Belediye kutuları vb.)
 type CollectionPoint struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OrgID     string             `bson:"org_id" json:"org_id"`
 	Name      string             `bson:"name" json:"name"`
 	Latitude  float64            `bson:"latitude" json:"latitude"`
 	Longitude float64            `bson:"longitude" json:"longitude"`
@@ -56,6 +58,7 @@ type CollectionPoint struct {
 type CollectionRequest struct {
 	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID            string             `bson:"user_id" json:"user_id"`
+	OrgID             string             `bson:"org_id" json:"org_id"`
 	WasteID           primitive.ObjectID `bson:"waste_id" json:"waste_id"`
 	CollectionPointID primitive.ObjectID `bson:"collection_point_id" json:"collection_point_id"`
 	Status            string             `bson:"status" json:"status"` // created, completed
@@ -78,6 +81,8 @@ type ImpactAnalysis struct {
 
 // --- INTERFACE'LER ---
 
+// WasteRepository sorguları context'teki Tenant'a göre sınırlar; Tenant yoksa ErrNoTenant döner.
+// Bulunamayan (veya başka organizasyona ait) kayıtlar için ErrNotFound döner.
 type WasteRepository interface {
 	Create(ctx context.Context, waste *Waste) error
 	UpdateAnalysis(ctx context.Context, id primitive.ObjectID, analysis *AIAnalysisResult) error
@@ -85,16 +90,18 @@ type WasteRepository interface {
 	GetWastes(ctx context.Context) ([]*Waste, error)
 	UpdateWasteStatus(ctx context.Context, id primitive.ObjectID, status string) error
 	DeleteWaste(ctx context.Context, id primitive.ObjectID) error
+	GetWaste(ctx context.Context, id primitive.ObjectID) (*Waste, error)
 	CreateRequest(ctx context.Context, req *CollectionRequest) error
-	// Demo verisi oluşturmak için (Harita noktaları boş kalmasın diye)
-	SeedPoints(ctx context.Context) error
-	SeedWastes(ctx context.Context) error
+	// Fixture'lar için: kayıt yoksa ekler, varsa dokunmaz
+	InsertPointIfMissing(ctx context.Context, point *CollectionPoint) (bool, error)
+	InsertWasteIfMissing(ctx context.Context, waste *Waste) (bool, error)
 	GetImpactStats(ctx context.Context) (*ImpactAnalysis, error)
 
 	// --- YENİ EKLENEN METODLAR (NOKTA YÖNETİMİ) ---
 	CreatePoint(ctx context.Context, point *CollectionPoint) error
 	UpdatePoint(ctx context.Context, id primitive.ObjectID, point *CollectionPoint) error
 	DeletePoint(ctx context.Context, id primitive.ObjectID) error
+	GetPoint(ctx context.Context, id primitive.ObjectID) (*CollectionPoint, error)
 }
 
 type WasteService interface {
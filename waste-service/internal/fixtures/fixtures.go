@@ -0,0 +1,108 @@
+// Package fixtures geliştirme ortamında haritanın ve istatistiklerin boş gelmemesi için
+// demo toplama noktalarını ve atık kayıtlarını yükler. Varsayılan olarak kapalıdır ve
+// production ortamında çalışmaz. Kayıtlar sadece eksikse eklenir; var olan veriye dokunulmaz.
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"waste-service/internal/domain"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var ErrProductionEnvironment = errors.New("fixtures are disabled in production")
+
+type WasteFixture struct {
+	UserID         string                   `json:"user_id"`
+	ImagePath      string                   `json:"image_path"` // Fixture kaydının doğal anahtarı
+	Description    string                   `json:"description"`
+	Status         string                   `json:"status"`
+	Category       string                   `json:"category"`
+	CreatedDaysAgo int                      `json:"created_days_ago"`
+	AIAnalysis     *domain.AIAnalysisResult `json:"ai_analysis"`
+}
+
+type File struct {
+	Points []domain.CollectionPoint `json:"points"` // Nokta adı anahtar olarak kullanılır
+	Wastes []WasteFixture           `json:"wastes"`
+}
+
+// Load .yaml/.yml veya .json uzantılı fixture dosyasını okur
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	if err := decode(path, data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Apply eksik noktaları ve atık kayıtlarını ekler. appEnv "production" ise hata döner.
+func Apply(ctx context.Context, appEnv string, repo domain.WasteRepository, file *File) error {
+	if strings.EqualFold(appEnv, "production") {
+		return ErrProductionEnvironment
+	}
+
+	for i := range file.Points {
+		point := file.Points[i]
+		inserted, err := repo.InsertPointIfMissing(ctx, &point)
+		if err != nil {
+			return fmt.Errorf("fixture point %s: %w", point.Name, err)
+		}
+		if inserted {
+			log.Printf("Fixture nokta eklendi: %s", point.Name)
+		}
+	}
+
+	for _, f := range file.Wastes {
+		waste := &domain.Waste{
+			UserID:      f.UserID,
+			ImagePath:   f.ImagePath,
+			Description: f.Description,
+			Status:      f.Status,
+			Category:    f.Category,
+			AIAnalysis:  f.AIAnalysis,
+			CreatedAt:   time.Now().AddDate(0, 0, -f.CreatedDaysAgo),
+		}
+		inserted, err := repo.InsertWasteIfMissing(ctx, waste)
+		if err != nil {
+			return fmt.Errorf("fixture waste %s: %w", f.ImagePath, err)
+		}
+		if inserted {
+			log.Printf("Fixture atık eklendi: %s", f.Description)
+		}
+	}
+	return nil
+}
+
+// decode YAML'ı JSON'a çevirerek çözer; struct'lardaki json tag'leri iki format için de geçerli olur
+func decode(path string, data []byte, v interface{}) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return json.Unmarshal(data, v)
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		converted, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(converted, v)
+	default:
+		return fmt.Errorf("unsupported fixture format %q", filepath.Ext(path))
+	}
+}
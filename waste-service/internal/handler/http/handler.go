@@ -1,8 +1,10 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"waste-service/internal/domain"
+	"waste-service/internal/middleware"
 
 	"github.com/labstack/echo/v4"
 )
@@ -92,7 +94,7 @@ func (h *WasteHandler) UpdateWasteStatus(c echo.Context) error {
 	}
 
 	if err := h.service.UpdateWasteStatus(c.Request().Context(), id, payload.Status); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return wasteError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Durum güncellendi"})
@@ -103,7 +105,7 @@ func (h *WasteHandler) DeleteWaste(c echo.Context) error {
 	id := c.Param("id")
 
 	if err := h.service.DeleteWaste(c.Request().Context(), id); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return wasteError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Kayıt silindi"})
@@ -116,7 +118,7 @@ type RequestPayload struct {
 }
 
 func (h *WasteHandler) CreateRequest(c echo.Context) error {
-	userID := c.Get("userID").(string)
+	userID := c.Get(middleware.ContextUserID).(string)
 
 	var payload RequestPayload
 	if err := c.Bind(&payload); err != nil {
@@ -125,7 +127,7 @@ func (h *WasteHandler) CreateRequest(c echo.Context) error {
 
 	req, err := h.service.CreateCollectionRequest(c.Request().Context(), userID, payload.WasteID, payload.PointID)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return wasteError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, req)
@@ -156,7 +158,7 @@ func (h *WasteHandler) UpdatePoint(c echo.Context) error {
 	}
 
 	if err := h.service.UpdatePoint(c.Request().Context(), id, &point); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return wasteError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Nokta güncellendi"})
@@ -167,7 +169,7 @@ func (h *WasteHandler) DeletePoint(c echo.Context) error {
 	id := c.Param("id")
 
 	if err := h.service.DeletePoint(c.Request().Context(), id); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return wasteError(c, err)
 	}
 
 	return c.JSON(http.StatusOK, map[string]string{"message": "Nokta silindi"})
@@ -260,21 +262,34 @@ func (h *WasteHandler) SubmitMultipleDevices(c echo.Context) error {
 	return c.JSON(http.StatusCreated, waste)
 }
 
+// wasteError bulunamayan ya da başka bir organizasyona ait kayıtlar için 404 döner
+func wasteError(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+}
+
 // Rotaları Tanımla (GÜNCELLENDİ)
 // NOT: /upload ve /points artık main.go'da public olarak tanımlı (auth gerektirmiyor)
 func (h *WasteHandler) RegisterRoutes(e *echo.Group) {
 	// e.POST("/upload", h.Upload)  // ❌ Bu satırı kaldırdık - main.go'da public
 	// e.GET("/points", h.GetPoints) // ❌ Bu satırı kaldırdık - main.go'da public
 
+	// Hangi rolün hangi işlemi yapabileceği auth servisindeki rol tanımlarından gelir
+	requirePerm := middleware.RequirePermission
+	// Silme işlemleri destek personelinin kullanıcı yerine geçtiği oturumlarda yapılamaz
+	denyImpersonation := middleware.DenyImpersonation()
+
 	e.GET("/wastes", h.GetWastes)
-	e.GET("/wastes/debug", h.GetWastesDebug)    // Debug endpoint
-	e.PATCH("/wastes/:id", h.UpdateWasteStatus) // Atık durumunu güncelle
-	e.DELETE("/wastes/:id", h.DeleteWaste)      // Atık sil
-	e.POST("/requests", h.CreateRequest)
-
-	e.POST("/points", h.CreatePoint)       // Nokta ekle
-	e.PUT("/points/:id", h.UpdatePoint)    // Nokta güncelle
-	e.DELETE("/points/:id", h.DeletePoint) // Nokta sil
+	e.GET("/wastes/debug", h.GetWastesDebug)                                                            // Debug endpoint
+	e.PATCH("/wastes/:id", h.UpdateWasteStatus, requirePerm(middleware.PermWastesUpdateStatus))         // Atık durumunu güncelle
+	e.DELETE("/wastes/:id", h.DeleteWaste, denyImpersonation, requirePerm(middleware.PermWastesDelete)) // Atık sil
+	e.POST("/requests", h.CreateRequest, requirePerm(middleware.PermWastesRequest))
+
+	e.POST("/points", h.CreatePoint, requirePerm(middleware.PermPointsManage))                          // Nokta ekle
+	e.PUT("/points/:id", h.UpdatePoint, requirePerm(middleware.PermPointsManage))                       // Nokta güncelle
+	e.DELETE("/points/:id", h.DeletePoint, denyImpersonation, requirePerm(middleware.PermPointsManage)) // Nokta sil
 
 	// NOT: /impact-analysis, /upload, /points main.go'da public olarak tanımlı
 }
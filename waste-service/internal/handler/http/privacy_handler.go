@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"waste-service/internal/domain"
+	"waste-service/internal/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PrivacyHandler struct {
+	service domain.PrivacyService
+}
+
+func NewPrivacyHandler(service domain.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{service: service}
+}
+
+// Export - Kullanıcının atık kayıtları, talepleri ve resimleri (ZIP)
+func (h *PrivacyHandler) Export(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user_id gerekli"})
+	}
+
+	data, err := h.service.Export(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="waste-data.zip"`)
+	return c.Blob(http.StatusOK, "application/zip", data)
+}
+
+// Erase - Resimleri siler, atık ve talepleri anonimleştirir.
+// Auth servisindeki silme akışı tarafından, kullanıcı hesabı silinmeden önce çağrılır.
+func (h *PrivacyHandler) Erase(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "user_id gerekli"})
+	}
+
+	result, err := h.service.Erase(c.Request().Context(), userID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// RegisterRoutes uçları kullanıcı token'larına kapalıdır; sadece auth servisi, ilgili scope'u
+// taşıyan servis token'ıyla ve hedef kullanıcıyı user_id ile belirterek çağırabilir.
+// Bu yüzden AuthGuard'lı gruba değil, ayrı bir gruba eklenmelidir.
+func (h *PrivacyHandler) RegisterRoutes(e *echo.Group, verifier *middleware.TokenVerifier) {
+	e.GET("/privacy/export", h.Export, middleware.ServiceGuard(verifier, middleware.ScopePrivacyExport))
+	e.POST("/privacy/erase", h.Erase, middleware.ServiceGuard(verifier, middleware.ScopePrivacyErase))
+}
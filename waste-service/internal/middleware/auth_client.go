@@ -1,49 +1,187 @@
 package middleware
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"net/http"
+	"strings"
+	"waste-service/internal/domain"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-// Auth servisinden dönecek cevap yapısı (ValidateToken endpointine göre)
-type ValidateResponse struct {
-	Valid  bool   `json:"valid"`
-	UserID string `json:"user_id"`
+// Handler'ların kimlik bilgisine ulaştığı context anahtarları
+const (
+	ContextUserID          = "userID"
+	ContextUserEmail       = "userEmail"
+	ContextUserRole        = "userRole"
+	ContextUserPermissions = "userPermissions"
+	ContextUserOrg         = "userOrg"
+	ContextImpersonatorID  = "impersonatorID" // Impersonation token'ında kullanıcının yerine geçen kişi
+)
+
+// HeaderAPIKey kiosk ve entegrasyonların kişisel API anahtarını gönderdiği başlık
+const HeaderAPIKey = "X-API-Key"
+
+// Identity doğrulanmış token'dan elde edilen kullanıcı bilgisi
+type Identity struct {
+	UserID      string
+	Email       string
+	Role        string
+	Permissions []string
+	SessionID   string
+	OrgID       string
+	Actor       *Actor // Impersonation token'ı değilse nil
+}
+
+// Actor impersonation token'ındaki "act" claim'i: kullanıcının yerine geçen destek personeli
+type Actor struct {
+	Sub   string `json:"sub"`
+	Email string `json:"email"`
+}
+
+// TokenVerifier token'ı önce auth servisinin public key'leriyle lokal olarak doğrular.
+// İmza anahtarı bulunamazsa (JWKS çekilemedi, bilinmeyen kid) auth servisine sorar.
+// Her iki durumda da oturumu iptal edilmiş token'lar reddedilir.
+type TokenVerifier struct {
+	keys        *KeySet
+	remote      *RemoteValidator
+	revocations *RevocationList
+}
+
+func NewTokenVerifier(keys *KeySet, remote *RemoteValidator, revocations *RevocationList) *TokenVerifier {
+	return &TokenVerifier{keys: keys, remote: remote, revocations: revocations}
 }
 
-func AuthGuard(authServiceURL string) echo.MiddlewareFunc {
+func (v *TokenVerifier) Verify(ctx context.Context, authHeader string) (*Identity, error) {
+	identity, err := v.verify(ctx, authHeader)
+	if err != nil {
+		return nil, err
+	}
+	// Remote doğrulama cache'lendiği için iptal listesine orada da bakılır
+	if identity.SessionID != "" && v.revocations.IsRevoked(identity.SessionID) {
+		return nil, errInvalidToken
+	}
+	return identity, nil
+}
+
+// VerifyAPIKey X-API-Key ile gelen anahtarı auth servisine sorar (oturumu olmadığı için iptal listesine bakılmaz)
+func (v *TokenVerifier) VerifyAPIKey(ctx context.Context, key string) (*Identity, error) {
+	return v.remote.ValidateAPIKey(ctx, key)
+}
+
+func (v *TokenVerifier) verify(ctx context.Context, authHeader string) (*Identity, error) {
+	if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
+		return nil, errInvalidToken
+	}
+	tokenString := authHeader[7:]
+
+	token, err := jwt.Parse(
+		tokenString,
+		v.keys.Keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, errKeyNotFound) {
+		// Lokal doğrulama yapılamıyor, auth servisine sor
+		return v.remote.Validate(ctx, tokenString)
+	}
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidToken
+	}
+	// Sadece kullanıcı access token'ı kabul edilir; üçüncü taraf OIDC istemcilerinin (delegated),
+	// servis istemcilerinin ve MFA/doğrulama token'larının imzası geçerli olsa da API'ye erişimi yoktur
+	if typ, _ := claims["typ"].(string); typ != "access" {
+		return nil, errInvalidToken
+	}
+	userID, _ := claims["user_id"].(string)
+	if userID == "" {
+		return nil, errInvalidToken
+	}
+	email, _ := claims["email"].(string)
+	role, _ := claims["role"].(string)
+	sessionID, _ := claims["sid"].(string)
+	orgID, _ := claims["org"].(string)
+
+	// JSON dizisi []interface{} olarak gelir
+	raw, _ := claims["permissions"].([]interface{})
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if s, ok := p.(string); ok {
+			permissions = append(permissions, s)
+		}
+	}
+
+	identity := &Identity{UserID: userID, Email: email, Role: role, Permissions: permissions, SessionID: sessionID, OrgID: organizationOrDefault(orgID)}
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		identity.Actor = &Actor{}
+		identity.Actor.Sub, _ = act["sub"].(string)
+		identity.Actor.Email, _ = act["email"].(string)
+	}
+	return identity, nil
+}
+
+func AuthGuard(verifier *TokenVerifier) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			// 1. Header'dan Token'ı al
+			// 1. Header'dan Token'ı al; Authorization yoksa kiosk/entegrasyon API anahtarı kabul edilir
 			authHeader := c.Request().Header.Get("Authorization")
-			if authHeader == "" {
+			apiKey := c.Request().Header.Get(HeaderAPIKey)
+			if authHeader == "" && apiKey == "" {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Token gerekli"})
 			}
 
-			// 2. Auth Servisine İstek At
-			client := &http.Client{}
-			// Auth servisi url'i config'den geliyor
-			req, _ := http.NewRequest("GET", authServiceURL+"/auth/validate", nil)
-			req.Header.Set("Authorization", authHeader)
+			// 2. Token'ı doğrula (lokal, gerekirse auth servisi üzerinden)
+			var identity *Identity
+			var err error
+			if authHeader != "" {
+				identity, err = verifier.Verify(c.Request().Context(), authHeader)
+			} else {
+				identity, err = verifier.VerifyAPIKey(c.Request().Context(), apiKey)
+			}
+			if errors.Is(err, errAuthServiceTimeout) {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Auth servisine ulaşılamıyor"})
+			}
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Token geçersiz"})
+			}
 
-			resp, err := client.Do(req)
-			if err != nil || resp.StatusCode != http.StatusOK {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Token geçersiz veya Auth servisi kapalı"})
+			// 3. Kimlik bilgilerini context'e koy (Handler'da kullanmak için)
+			c.Set(ContextUserID, identity.UserID)
+			c.Set(ContextUserEmail, identity.Email)
+			c.Set(ContextUserRole, identity.Role)
+			c.Set(ContextUserPermissions, identity.Permissions)
+			c.Set(ContextUserOrg, identity.OrgID)
+			actor := domain.AuditActor{
+				UserID:    identity.UserID,
+				Email:     identity.Email,
+				IP:        c.RealIP(),
+				UserAgent: c.Request().UserAgent(),
+			}
+			if identity.Actor != nil {
+				c.Set(ContextImpersonatorID, identity.Actor.Sub)
+				actor.ImpersonatorID = identity.Actor.Sub
+				actor.ImpersonatorEmail = identity.Actor.Email
 			}
-			defer resp.Body.Close()
 
-			// 3. Gelen cevabı oku ve UserID'yi al
-			var valResp ValidateResponse
-			if err := json.NewDecoder(resp.Body).Decode(&valResp); err != nil {
-				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Auth yanıtı okunamadı"})
+			// Repository sorguları bu organizasyonla sınırlanır
+			tenant, err := identityTenant(c, identity)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 			}
 
-			// 4. UserID'yi context'e koy (Handler'da kullanmak için)
-			c.Set("userID", valResp.UserID)
+			// Audit kayıtları işlemi yapanı request context'inden okur
+			ctx := domain.WithAuditActor(c.Request().Context(), actor)
+			ctx = domain.WithTenant(ctx, tenant)
+			c.SetRequest(c.Request().WithContext(ctx))
 
 			return next(c)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker(3, time.Hour)
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatalf("breaker opened after %d failures", i)
+		}
+		b.Failure()
+	}
+	if !b.Allow() {
+		t.Fatal("breaker opened before threshold")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker should be open after threshold failures")
+	}
+}
+
+func TestCircuitBreakerSuccessResets(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Hour)
+	b.Failure()
+	b.Success()
+	b.Failure()
+	// Aradaki başarı sayacı sıfırladığı için art arda iki hata yok
+	if !b.Allow() {
+		t.Fatal("breaker should be closed: failures were not consecutive")
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b := NewCircuitBreaker(1, 20*time.Millisecond)
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker should reject during cooldown")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	// Cooldown sonrası tek bir deneme isteği geçer
+	if !b.Allow() {
+		t.Fatal("breaker should allow a trial request after cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("only one trial request is allowed while half-open")
+	}
+
+	// Deneme başarısız olursa devre yeniden açılır
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker should reopen after a failed trial")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("breaker should allow a new trial after cooldown")
+	}
+	b.Success()
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("breaker should close after a successful trial")
+	}
+}
+
+func TestCircuitBreakerMinimumThreshold(t *testing.T) {
+	b := NewCircuitBreaker(0, time.Hour)
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("threshold below 1 should behave as 1")
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientTokenLeeway token süresi dolmadan bu kadar önce yenilenir
+const clientTokenLeeway = 30 * time.Second
+
+type clientTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// ClientCredentials auth servisinden client_credentials grant'i ile servis token'ı alır.
+// Token süresi dolmadan kısa süre öncesine kadar cache'lenir.
+type ClientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewClientCredentials(authServiceURL, clientID, clientSecret string, timeout time.Duration) *ClientCredentials {
+	return &ClientCredentials{
+		tokenURL:     authServiceURL + "/oauth/token",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: timeout},
+	}
+}
+
+// SetBasicAuth isteğe client_secret_basic kimliğini ekler (RFC 6749 2.3.1: değerler form-urlencoded)
+func (cc *ClientCredentials) SetBasicAuth(req *http.Request) {
+	req.SetBasicAuth(url.QueryEscape(cc.clientID), url.QueryEscape(cc.clientSecret))
+}
+
+// Token geçerli servis token'ını döner, gerekirse auth servisinden yenisini alır
+func (cc *ClientCredentials) Token(ctx context.Context) (string, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.token != "" && time.Now().Before(cc.expiresAt) {
+		return cc.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cc.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	cc.SetBasicAuth(req)
+
+	resp, err := cc.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+
+	var tokenResp clientTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", err
+	}
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint returned no access token")
+	}
+
+	cc.token = tokenResp.AccessToken
+	cc.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn)*time.Second - clientTokenLeeway)
+	return cc.token, nil
+}
+
+// Invalidate cache'teki token'ı siler (istemci silindi veya secret değişti)
+func (cc *ClientCredentials) Invalidate() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.token = ""
+}
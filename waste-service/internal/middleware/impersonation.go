@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DenyImpersonation silme ve kişisel veri uçlarını impersonation token'larına kapatır;
+// destek personeli kullanıcının gözünden bakabilir ama geri alınamaz işlem yapamaz.
+// AuthGuard'dan sonra kullanılmalıdır.
+func DenyImpersonation() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if impersonator, _ := c.Get(ContextImpersonatorID).(string); impersonator != "" {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Bu işlem kullanıcı yerine geçilmişken yapılamaz"})
+			}
+			return next(c)
+		}
+	}
+}
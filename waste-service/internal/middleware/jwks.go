@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// errKeyNotFound token'ın imza anahtarı elimizde yoksa döner; bu durumda remote doğrulamaya düşülür
+var errKeyNotFound = errors.New("signing key not found")
+
+const jwksMissRefreshWait = 30 * time.Second // Bilinmeyen "kid" için en sık JWKS çekme aralığı
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+}
+
+type publicKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet auth servisinin public key'lerini tutar. Anahtarlar JWKS endpoint'inden
+// arka planda yenilenir; opsiyonel olarak dosyadan verilen sabit bir anahtar da kullanılabilir.
+type KeySet struct {
+	jwksURL   string
+	client    *http.Client
+	static    *publicKey
+	mu        sync.RWMutex
+	keys      map[string]publicKey
+	lastFetch time.Time
+}
+
+func NewKeySet(jwksURL, publicKeyFile string, timeout time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		jwksURL: jwksURL,
+		client:  &http.Client{Timeout: timeout},
+		keys:    map[string]publicKey{},
+	}
+
+	if publicKeyFile != "" {
+		static, err := loadPublicKeyFile(publicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ks.static = static
+	}
+	return ks, nil
+}
+
+// Start JWKS'yi hemen çeker ve belirtilen aralıkla yenilemeye devam eder
+func (ks *KeySet) Start(ctx context.Context, interval time.Duration) {
+	if err := ks.Refresh(ctx); err != nil {
+		log.Printf("JWKS fetch error (remote doğrulama kullanılacak): %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ks.Refresh(ctx); err != nil {
+					log.Printf("JWKS refresh error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	if ks.jwksURL == "" {
+		return nil
+	}
+
+	ks.mu.Lock()
+	ks.lastFetch = time.Now()
+	ks.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.jwksURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := map[string]publicKey{}
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("Skipping JWK %s: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = publicKey{alg: k.Alg, key: key}
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+// Keyfunc jwt.Parse için "kid"e göre public key döner
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.lookup(kid)
+	if !ok && kid != "" && ks.refreshAllowed() {
+		// Auth servisi anahtar rotasyonu yapmış olabilir
+		if err := ks.Refresh(context.Background()); err != nil {
+			log.Printf("JWKS refresh error: %v", err)
+		}
+		key, ok = ks.lookup(kid)
+	}
+	if !ok {
+		if ks.static == nil {
+			return nil, errKeyNotFound
+		}
+		key = *ks.static
+	}
+
+	if key.alg != "" && token.Method.Alg() != key.alg {
+		return nil, errors.New("signing method does not match key")
+	}
+	return key.key, nil
+}
+
+func (ks *KeySet) lookup(kid string) (publicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+func (ks *KeySet) refreshAllowed() bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return time.Since(ks.lastFetch) >= jwksMissRefreshWait
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func loadPublicKeyFile(path string) (*publicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return &publicKey{alg: jwt.SigningMethodRS256.Alg(), key: key}, nil
+	case ed25519.PublicKey:
+		return &publicKey{alg: jwt.SigningMethodEdDSA.Alg(), key: key}, nil
+	default:
+		return nil, errors.New("unsupported public key type")
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testJWKS struct {
+	server  *httptest.Server
+	keys    []jwk
+	fetches atomic.Int32
+}
+
+func newTestJWKS(t *testing.T, keys ...jwk) *testJWKS {
+	t.Helper()
+	s := &testJWKS{keys: keys}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func rsaJWK(t *testing.T, kid string) (*rsa.PrivateKey, jwk) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, jwk{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: jwt.SigningMethodRS256.Alg(),
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ed25519JWK(t *testing.T, kid string) (ed25519.PrivateKey, jwk) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private, jwk{Kty: "OKP", Kid: kid, Alg: jwt.SigningMethodEdDSA.Alg(), Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(public)}
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func parseWith(ks *KeySet, token string) error {
+	_, err := jwt.Parse(token, ks.Keyfunc, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}))
+	return err
+}
+
+func TestKeySetRefreshParsesKeys(t *testing.T) {
+	rsaKey, rsaPublic := rsaJWK(t, "rsa-1")
+	edKey, edPublic := ed25519JWK(t, "ed-1")
+	// Desteklenmeyen ve bozuk anahtarlar atlanır, diğerleri yüklenir
+	unsupported := jwk{Kty: "EC", Kid: "ec-1", Crv: "P-256"}
+	badCurve := jwk{Kty: "OKP", Kid: "x448", Crv: "X448", X: edPublic.X}
+	shortKey := jwk{Kty: "OKP", Kid: "short", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString([]byte("short"))}
+	jwks := newTestJWKS(t, rsaPublic, edPublic, unsupported, badCurve, shortKey)
+
+	ks, err := NewKeySet(jwks.server.URL, "", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	for _, kid := range []string{"ec-1", "x448", "short"} {
+		if _, ok := ks.lookup(kid); ok {
+			t.Errorf("key %s should have been skipped", kid)
+		}
+	}
+	if err := parseWith(ks, signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey)); err != nil {
+		t.Errorf("RS256 token rejected: %v", err)
+	}
+	if err := parseWith(ks, signToken(t, jwt.SigningMethodEdDSA, "ed-1", edKey)); err != nil {
+		t.Errorf("EdDSA token rejected: %v", err)
+	}
+}
+
+func TestKeySetRejectsAlgorithmMismatch(t *testing.T) {
+	_, rsaPublic := rsaJWK(t, "rsa-1")
+	edKey, _ := ed25519JWK(t, "ed-1")
+	jwks := newTestJWKS(t, rsaPublic)
+
+	ks, _ := NewKeySet(jwks.server.URL, "", time.Second)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// RSA anahtarının kid'iyle EdDSA imzalı token kabul edilmez
+	if err := parseWith(ks, signToken(t, jwt.SigningMethodEdDSA, "rsa-1", edKey)); err == nil {
+		t.Fatal("token signed with a different algorithm was accepted")
+	}
+}
+
+func TestKeySetUnknownKid(t *testing.T) {
+	_, rsaPublic := rsaJWK(t, "rsa-1")
+	rotatedKey, rotatedPublic := rsaJWK(t, "rsa-2")
+	jwks := newTestJWKS(t, rsaPublic)
+
+	ks, _ := NewKeySet(jwks.server.URL, "", time.Second)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	token := signToken(t, jwt.SigningMethodRS256, "rsa-2", rotatedKey)
+
+	// Son çekimden bu yana bekleme süresi dolmadıysa JWKS tekrar çekilmez; remote doğrulamaya düşülür
+	if err := parseWith(ks, token); !errors.Is(err, errKeyNotFound) {
+		t.Fatalf("expected errKeyNotFound, got %v", err)
+	}
+	if got := jwks.fetches.Load(); got != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", got)
+	}
+
+	// Auth servisi anahtarı döndürdü; bekleme süresi geçince bilinmeyen kid JWKS'yi yeniler
+	jwks.keys = []jwk{rsaPublic, rotatedPublic}
+	ks.mu.Lock()
+	ks.lastFetch = time.Now().Add(-jwksMissRefreshWait)
+	ks.mu.Unlock()
+	if err := parseWith(ks, token); err != nil {
+		t.Fatalf("token with rotated key rejected: %v", err)
+	}
+	if got := jwks.fetches.Load(); got != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", got)
+	}
+}
+
+func TestKeySetRefreshError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ks, _ := NewKeySet(server.URL, "", time.Second)
+	if err := ks.Refresh(context.Background()); err == nil {
+		t.Fatal("expected error for non-200 JWKS response")
+	}
+}
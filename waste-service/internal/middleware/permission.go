@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Waste servisinin kullandığı yetkiler; rol → yetki eşlemesi auth servisinde yönetilir
+const (
+	PermWastesRequest      = "wastes:request"
+	PermWastesUpdateStatus = "wastes:update_status"
+	PermWastesDelete       = "wastes:delete"
+	PermPointsManage       = "points:manage"
+)
+
+// RequirePermission token'daki yetkiler arasında verilen yetki yoksa 403 döner.
+// AuthGuard'dan sonra kullanılmalıdır.
+func RequirePermission(permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			permissions, _ := c.Get(ContextUserPermissions).([]string)
+			if !hasPermission(permissions, permission) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Bu işlem için yetkiniz yok"})
+			}
+			return next(c)
+		}
+	}
+}
+
+// hasPermission "*" ve "kaynak:*" joker karakterlerini de dikkate alır
+func hasPermission(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+	for _, p := range granted {
+		if p == "*" || p == required || p == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
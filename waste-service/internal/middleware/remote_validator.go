@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errInvalidToken       = errors.New("invalid token")
+	errAuthServiceTimeout = errors.New("auth service unavailable")
+)
+
+// Auth servisinden dönecek cevap yapısı (ValidateToken endpointine göre)
+type ValidateResponse struct {
+	Valid       bool     `json:"valid"`
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"session_id"`
+	OrgID       string   `json:"org"`
+	Act         *Actor   `json:"act"`
+}
+
+// Auth servisinin /oauth/introspect cevabı (RFC 7662)
+type introspectionResponse struct {
+	Active      bool     `json:"active"`
+	Kind        string   `json:"typ"`
+	Sub         string   `json:"sub"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	SessionID   string   `json:"sid"`
+	OrgID       string   `json:"org"`
+	Act         *Actor   `json:"act"`
+}
+
+type cachedValidation struct {
+	identity  *Identity
+	expiresAt time.Time
+}
+
+// RemoteValidator token'ı auth servisine sorar. Servis kimliği tanımlıysa /oauth/introspect,
+// değilse eski /auth/validate endpoint'i kullanılır.
+// Başarılı sonuçlar kısa süre cache'lenir, auth servisi hata verdikçe devre kesici açılır.
+type RemoteValidator struct {
+	validateURL   string
+	introspectURL string
+	credentials   *ClientCredentials // nil ise /auth/validate kullanılır
+	client        *http.Client
+	cacheTTL      time.Duration
+	breaker       *CircuitBreaker
+
+	mu    sync.Mutex
+	cache map[string]cachedValidation
+}
+
+func NewRemoteValidator(authServiceURL string, credentials *ClientCredentials, timeout, cacheTTL time.Duration, breaker *CircuitBreaker) *RemoteValidator {
+	return &RemoteValidator{
+		validateURL:   authServiceURL + "/auth/validate",
+		introspectURL: authServiceURL + "/oauth/introspect",
+		credentials:   credentials,
+		client:        &http.Client{Timeout: timeout},
+		cacheTTL:      cacheTTL,
+		breaker:       breaker,
+		cache:         map[string]cachedValidation{},
+	}
+}
+
+// Validate Bearer access token'ı doğrular
+func (r *RemoteValidator) Validate(ctx context.Context, token string) (*Identity, error) {
+	return r.validate(ctx, token, false)
+}
+
+// ValidateAPIKey X-API-Key ile gelen kişisel anahtarı doğrular. Anahtarlar JWT olmadığı için
+// her zaman auth servisine sorulur; iptal edilen anahtar en geç cache süresi sonunda reddedilir.
+func (r *RemoteValidator) ValidateAPIKey(ctx context.Context, key string) (*Identity, error) {
+	return r.validate(ctx, key, true)
+}
+
+func (r *RemoteValidator) validate(ctx context.Context, token string, apiKey bool) (*Identity, error) {
+	key := cacheKey(token)
+	if identity, ok := r.cached(key); ok {
+		return identity, nil
+	}
+
+	if !r.breaker.Allow() {
+		return nil, errAuthServiceTimeout
+	}
+
+	req, err := r.newRequest(ctx, token, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		r.breaker.Failure()
+		return nil, errAuthServiceTimeout
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		r.breaker.Failure()
+		return nil, errAuthServiceTimeout
+	}
+	// 4xx cevaplar auth servisinin sağlıklı olduğunu gösterir, sadece token geçersizdir
+	r.breaker.Success()
+
+	if r.credentials != nil && resp.StatusCode == http.StatusUnauthorized {
+		// Servis kimliği reddedildi; kullanıcının token'ı hakkında bilgi yok
+		log.Printf("Token introspection rejected the service client credentials")
+		return nil, errAuthServiceTimeout
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errInvalidToken
+	}
+
+	var identity *Identity
+	if r.credentials != nil {
+		kind := "access"
+		if apiKey {
+			kind = "api_key"
+		}
+		identity, err = decodeIntrospection(resp, kind)
+	} else {
+		identity, err = decodeValidation(resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.store(key, identity)
+	return identity, nil
+}
+
+func (r *RemoteValidator) newRequest(ctx context.Context, token string, apiKey bool) (*http.Request, error) {
+	if r.credentials == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.validateURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if apiKey {
+			req.Header.Set(HeaderAPIKey, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	}
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.introspectURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.credentials.SetBasicAuth(req)
+	return req, nil
+}
+
+// decodeIntrospection kind beklenen kimlik türüdür: Bearer için "access", X-API-Key için "api_key"
+func decodeIntrospection(resp *http.Response, kind string) (*Identity, error) {
+	var result introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("auth yanıtı okunamadı: %w", err)
+	}
+	// Servis token'ları ve üçüncü taraf (delegated) token'lar kullanıcı API'sine erişemez
+	if !result.Active || result.Kind != kind || result.Sub == "" {
+		return nil, errInvalidToken
+	}
+	return &Identity{UserID: result.Sub, Email: result.Username, Role: result.Role, Permissions: result.Permissions, SessionID: result.SessionID, OrgID: organizationOrDefault(result.OrgID), Actor: result.Act}, nil
+}
+
+func decodeValidation(resp *http.Response) (*Identity, error) {
+	var valResp ValidateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&valResp); err != nil {
+		return nil, fmt.Errorf("auth yanıtı okunamadı: %w", err)
+	}
+	if !valResp.Valid {
+		return nil, errInvalidToken
+	}
+	return &Identity{UserID: valResp.UserID, Email: valResp.Email, Role: valResp.Role, Permissions: valResp.Permissions, SessionID: valResp.SessionID, OrgID: organizationOrDefault(valResp.OrgID), Actor: valResp.Act}, nil
+}
+
+func (r *RemoteValidator) cached(key string) (*Identity, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(r.cache, key)
+		return nil, false
+	}
+	return entry.identity, true
+}
+
+func (r *RemoteValidator) store(key string, identity *Identity) {
+	if r.cacheTTL <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	// Süresi dolmuş kayıtları temizle ki cache sınırsız büyümesin
+	for k, entry := range r.cache {
+		if now.After(entry.expiresAt) {
+			delete(r.cache, k)
+		}
+	}
+	r.cache[key] = cachedValidation{identity: identity, expiresAt: now.Add(r.cacheTTL)}
+}
+
+// cacheKey token'ı bellekte düz metin tutmamak için hash'ler
+func cacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// revocationOverlap sorgu ile eşzamanlı yazılan iptallerin kaçmaması için since biraz geri alınır
+const revocationOverlap = 30 * time.Second
+
+type revokedSessionFeed struct {
+	Sessions []struct {
+		SessionID string    `json:"session_id"`
+		RevokedAt time.Time `json:"revoked_at"`
+	} `json:"sessions"`
+	Until time.Time `json:"until"`
+}
+
+// RevocationList auth servisinde iptal edilen oturumları (logout, cihaz kapatma, admin iptali)
+// arka planda çeker. Lokal doğrulanan token'lar imzası geçerli olsa da oturumu iptal
+// edildiyse reddedilir. Akış çekilemezse token'lar kabul edilmeye devam eder (JWKS gibi).
+// Akış sessions:revoked scope'lu servis token'ı ister.
+type RevocationList struct {
+	feedURL     string
+	client      *http.Client
+	credentials *ClientCredentials // nil ise akış çekilemez
+	ttl         time.Duration      // Access token ömründen uzun olmalı
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+	since   time.Time
+}
+
+func NewRevocationList(authServiceURL string, credentials *ClientCredentials, timeout, ttl time.Duration) *RevocationList {
+	return &RevocationList{
+		feedURL:     authServiceURL + "/auth/sessions/revoked",
+		client:      &http.Client{Timeout: timeout},
+		credentials: credentials,
+		ttl:         ttl,
+		revoked:     map[string]time.Time{},
+	}
+}
+
+// Start listeyi hemen çeker ve belirtilen aralıkla güncellemeye devam eder
+func (r *RevocationList) Start(ctx context.Context, interval time.Duration) {
+	if err := r.Refresh(ctx); err != nil {
+		log.Printf("Revoked sessions fetch error: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.Refresh(ctx); err != nil {
+					log.Printf("Revoked sessions refresh error: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *RevocationList) Refresh(ctx context.Context) error {
+	if r.credentials == nil {
+		return errors.New("service client credentials are not configured")
+	}
+	token, err := r.credentials.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("service token: %w", err)
+	}
+
+	r.mu.RLock()
+	since := r.since
+	r.mu.RUnlock()
+
+	feedURL := r.feedURL
+	if !since.IsZero() {
+		feedURL += "?since=" + url.QueryEscape(since.Add(-revocationOverlap).Format(time.RFC3339Nano))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// İstemci silinmiş ya da secret değişmiş olabilir; bir sonraki denemede yeni token alınır
+		r.credentials.Invalidate()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("revoked sessions endpoint returned %d", resp.StatusCode)
+	}
+
+	var feed revokedSessionFeed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range feed.Sessions {
+		r.revoked[s.SessionID] = s.RevokedAt
+	}
+	// Access token'ı çoktan dolmuş oturumları tutmaya gerek yok
+	cutoff := time.Now().Add(-r.ttl)
+	for id, revokedAt := range r.revoked {
+		if revokedAt.Before(cutoff) {
+			delete(r.revoked, id)
+		}
+	}
+	r.since = feed.Until
+	return nil
+}
+
+func (r *RevocationList) IsRevoked(sessionID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.revoked[sessionID]
+	return ok
+}
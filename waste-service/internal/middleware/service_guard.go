@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"waste-service/internal/domain"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// Auth servisinin privacy istemcisine verdiği scope'lar (auth: domain.PrivacyScopes)
+const (
+	ScopePrivacyExport = "privacy:export"
+	ScopePrivacyErase  = "privacy:erase"
+)
+
+// ContextClientID servis rotalarında isteği yapan istemci
+const ContextClientID = "clientID"
+
+var errInsufficientScope = errors.New("insufficient scope")
+
+// VerifyClient auth servisinin client_credentials token'ını lokal olarak doğrular ve istemci
+// ID'sini döner. Kullanıcı token'ları ve istenen scope'u taşımayan token'lar reddedilir.
+func (v *TokenVerifier) VerifyClient(authHeader, scope string) (string, error) {
+	if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
+		return "", errInvalidToken
+	}
+
+	token, err := jwt.Parse(
+		authHeader[7:],
+		v.keys.Keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if errors.Is(err, errKeyNotFound) {
+		// Servis token'ları introspection'a gönderilmez; anahtar gelene kadar istek tekrarlanmalı
+		return "", errAuthServiceTimeout
+	}
+	if err != nil || !token.Valid {
+		return "", errInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", errInvalidToken
+	}
+	if typ, _ := claims["typ"].(string); typ != "client" {
+		return "", errInvalidToken
+	}
+	clientID, _ := claims["client_id"].(string)
+	if clientID == "" {
+		return "", errInvalidToken
+	}
+	granted, _ := claims["scope"].(string)
+	if !slices.Contains(strings.Fields(granted), scope) {
+		return "", errInsufficientScope
+	}
+	return clientID, nil
+}
+
+// ServiceGuard rotayı sadece auth servisinin istemci token'larına açar; kullanıcı token'ları
+// ve API anahtarları kabul edilmez. İşlemin yapılacağı kullanıcı isteğin kendisinde gelir.
+func ServiceGuard(verifier *TokenVerifier, scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			clientID, err := verifier.VerifyClient(c.Request().Header.Get("Authorization"), scope)
+			if errors.Is(err, errAuthServiceTimeout) {
+				return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Auth servisine ulaşılamıyor"})
+			}
+			if errors.Is(err, errInsufficientScope) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "Yetersiz scope"})
+			}
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Servis token'ı gerekli"})
+			}
+
+			c.Set(ContextClientID, clientID)
+			// Audit kayıtlarında işlemi yapan servis istemcisi görünür
+			ctx := domain.WithAuditActor(c.Request().Context(), domain.AuditActor{
+				UserID:    clientID,
+				IP:        c.RealIP(),
+				UserAgent: c.Request().UserAgent(),
+			})
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"waste-service/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Organizasyon ID'leri auth servisindeki slug kuralına uyar
+var organizationIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,47}$`)
+
+var errInvalidOrganization = errors.New("Geçersiz organizasyon")
+
+// organizationOrDefault "org" claim'i olmayan eski token'ları varsayılan organizasyona bağlar
+func organizationOrDefault(orgID string) string {
+	if orgID == "" {
+		return domain.DefaultOrganizationID
+	}
+	return orgID
+}
+
+// identityTenant kullanıcının sorgularının sınırlanacağı organizasyonu belirler. Süper admin'ler
+// ?org= ile tek bir organizasyonu seçebilir; seçmezlerse tüm organizasyonları görür.
+func identityTenant(c echo.Context, identity *Identity) (domain.Tenant, error) {
+	tenant := domain.Tenant{OrgID: identity.OrgID}
+	if identity.Role != domain.RoleSuperAdmin {
+		return tenant, nil
+	}
+
+	org := c.QueryParam("org")
+	if org == "" {
+		tenant.AllOrganizations = true
+		return tenant, nil
+	}
+	if !organizationIDPattern.MatchString(org) {
+		return tenant, errInvalidOrganization
+	}
+	tenant.OrgID = org
+	return tenant, nil
+}
+
+// PublicTenant giriş gerektirmeyen rotalarda organizasyonu ?org= parametresinden alır,
+// verilmezse varsayılan organizasyon kullanılır.
+func PublicTenant() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			org := organizationOrDefault(c.QueryParam("org"))
+			if !organizationIDPattern.MatchString(org) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": errInvalidOrganization.Error()})
+			}
+
+			ctx := domain.WithTenant(c.Request().Context(), domain.Tenant{OrgID: org})
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
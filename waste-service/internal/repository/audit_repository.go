@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"waste-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoAuditRepository struct {
+	db *mongo.Database
+}
+
+// NewMongoAuditRepository audit_db veritabanını alır (auth servisiyle ortak)
+func NewMongoAuditRepository(db *mongo.Database) domain.AuditRepository {
+	return &mongoAuditRepository{db: db}
+}
+
+func (m *mongoAuditRepository) Append(ctx context.Context, event *domain.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	event.ID = primitive.NewObjectID()
+	_, err := m.db.Collection("audit_events").InsertOne(ctx, event)
+	return err
+}
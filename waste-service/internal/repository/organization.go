@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BackfillOrganization organizasyon öncesi kayıtları verilen organizasyona taşır ve sorguların
+// kullandığı org_id index'lerini oluşturur. Sadece eksik alanlara dokunduğu için her açılışta çalışabilir.
+func BackfillOrganization(ctx context.Context, db *mongo.Database, orgID string) error {
+	for _, name := range []string{"wastes", "points", "requests"} {
+		collection := db.Collection(name)
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"org_id": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"org_id": orgID}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	indexes := map[string][]mongo.IndexModel{
+		"wastes": {
+			{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "status", Value: 1}}, Options: options.Index().SetName("org_id_status")},
+		},
+		"points": {
+			{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetName("org_id_name")},
+		},
+		"requests": {
+			{Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetName("org_id_created_at")},
+		},
+	}
+	for name, models := range indexes {
+		if _, err := db.Collection(name).Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
+	return nil
+}
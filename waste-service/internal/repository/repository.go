@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 	"waste-service/internal/domain"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoRepository struct {
@@ -18,28 +20,66 @@ func NewMongoRepository(db *mongo.Database) domain.WasteRepository {
 	return &mongoRepository{db: db}
 }
 
+// NewMongoPrivacyRepository kişisel veri dışa aktarma/silme sorguları; aynı koleksiyonları kullanır
+func NewMongoPrivacyRepository(db *mongo.Database) domain.PrivacyRepository {
+	return &mongoRepository{db: db}
+}
+
+// scoped filtreyi context'teki organizasyonla sınırlar. Tenant yoksa sorgu çalıştırılmaz.
+func scoped(ctx context.Context, filter bson.M) (bson.M, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok || tenant.OrgID == "" {
+		return nil, domain.ErrNoTenant
+	}
+	if !tenant.AllOrganizations {
+		filter["org_id"] = tenant.OrgID
+	}
+	return filter, nil
+}
+
+// tenantOrg yeni kayıtların yazılacağı organizasyon
+func tenantOrg(ctx context.Context) (string, error) {
+	tenant, ok := domain.TenantFromContext(ctx)
+	if !ok || tenant.OrgID == "" {
+		return "", domain.ErrNoTenant
+	}
+	return tenant.OrgID, nil
+}
+
 func (m *mongoRepository) Create(ctx context.Context, waste *domain.Waste) error {
+	orgID, err := tenantOrg(ctx)
+	if err != nil {
+		return err
+	}
+	waste.OrgID = orgID
 	waste.ID = primitive.NewObjectID()
 	waste.CreatedAt = time.Now()
-	_, err := m.db.Collection("wastes").InsertOne(ctx, waste)
+	_, err = m.db.Collection("wastes").InsertOne(ctx, waste)
 	return err
 }
 
 func (m *mongoRepository) UpdateAnalysis(ctx context.Context, id primitive.ObjectID, analysis *domain.AIAnalysisResult) error {
-	filter := bson.M{"_id": id}
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"ai_analysis": analysis,
 			"status":      "analyzed",
 		},
 	}
-	_, err := m.db.Collection("wastes").UpdateOne(ctx, filter, update)
+	_, err = m.db.Collection("wastes").UpdateOne(ctx, filter, update)
 	return err
 }
 
 func (m *mongoRepository) GetAllPoints(ctx context.Context) ([]*domain.CollectionPoint, error) {
+	filter, err := scoped(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
 	var points []*domain.CollectionPoint
-	cursor, err := m.db.Collection("points").Find(ctx, bson.M{})
+	cursor, err := m.db.Collection("points").Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +90,12 @@ func (m *mongoRepository) GetAllPoints(ctx context.Context) ([]*domain.Collectio
 }
 
 func (m *mongoRepository) GetWastes(ctx context.Context) ([]*domain.Waste, error) {
+	filter, err := scoped(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
 	var wastes []*domain.Waste
-	cursor, err := m.db.Collection("wastes").Find(ctx, bson.M{})
+	cursor, err := m.db.Collection("wastes").Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -63,55 +107,134 @@ func (m *mongoRepository) GetWastes(ctx context.Context) ([]*domain.Waste, error
 
 func (m *mongoRepository) UpdateWasteStatus(ctx context.Context, id primitive.ObjectID, status string) error {
 	collection := m.db.Collection("wastes")
-	filter := bson.M{"_id": id}
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"status": status,
 		},
 	}
-	_, err := collection.UpdateOne(ctx, filter, update)
-	return err
+	res, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
 func (m *mongoRepository) DeleteWaste(ctx context.Context, id primitive.ObjectID) error {
 	collection := m.db.Collection("wastes")
-	filter := bson.M{"_id": id}
-	_, err := collection.DeleteOne(ctx, filter)
-	return err
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	res, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
 func (m *mongoRepository) CreateRequest(ctx context.Context, req *domain.CollectionRequest) error {
+	orgID, err := tenantOrg(ctx)
+	if err != nil {
+		return err
+	}
+	req.OrgID = orgID
 	req.ID = primitive.NewObjectID()
 	req.CreatedAt = time.Now()
-	_, err := m.db.Collection("requests").InsertOne(ctx, req)
+	_, err = m.db.Collection("requests").InsertOne(ctx, req)
 	return err
 }
 
-// Proje ilk açıldığında haritada nokta görünsün diye sahte veri basar
-func (m *mongoRepository) SeedPoints(ctx context.Context) error {
-	count, _ := m.db.Collection("points").CountDocuments(ctx, bson.M{})
-	if count > 0 {
-		return nil
+// InsertPointIfMissing organizasyonda aynı isimde nokta yoksa ekler (fixture'lar için)
+func (m *mongoRepository) InsertPointIfMissing(ctx context.Context, point *domain.CollectionPoint) (bool, error) {
+	orgID, err := tenantOrg(ctx)
+	if err != nil {
+		return false, err
 	}
+	res, err := m.db.Collection("points").UpdateOne(
+		ctx,
+		bson.M{"name": point.Name, "org_id": orgID},
+		bson.M{"$setOnInsert": bson.M{
+			"latitude":  point.Latitude,
+			"longitude": point.Longitude,
+			"address":   point.Address,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount == 1, nil
+}
 
-	points := []interface{}{
-		domain.CollectionPoint{Name: "KTÜ Toplama Merkezi", Latitude: 40.995, Longitude: 39.771, Address: "Kanuni Kampüsü"},
-		domain.CollectionPoint{Name: "Meydan Geri Dönüşüm", Latitude: 41.005, Longitude: 39.722, Address: "Meydan Parkı Yanı"},
+// InsertWasteIfMissing aynı görsel yoluna sahip kayıt yoksa ekler (fixture'lar için)
+func (m *mongoRepository) InsertWasteIfMissing(ctx context.Context, waste *domain.Waste) (bool, error) {
+	orgID, err := tenantOrg(ctx)
+	if err != nil {
+		return false, err
 	}
-	_, err := m.db.Collection("points").InsertMany(ctx, points)
-	return err
+	res, err := m.db.Collection("wastes").UpdateOne(
+		ctx,
+		bson.M{"image_path": waste.ImagePath, "org_id": orgID},
+		bson.M{"$setOnInsert": bson.M{
+			"user_id":     waste.UserID,
+			"description": waste.Description,
+			"ai_analysis": waste.AIAnalysis,
+			"status":      waste.Status,
+			"category":    waste.Category,
+			"is_multiple": waste.IsMultiple,
+			"created_at":  waste.CreatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.UpsertedCount == 1, nil
+}
+
+func (m *mongoRepository) GetWaste(ctx context.Context, id primitive.ObjectID) (*domain.Waste, error) {
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
+	}
+	var waste domain.Waste
+	if err := m.db.Collection("wastes").FindOne(ctx, filter).Decode(&waste); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &waste, nil
 }
 
 // --- YENİ EKLENEN METODLAR (NOKTA YÖNETİMİ) ---
 
 func (m *mongoRepository) CreatePoint(ctx context.Context, point *domain.CollectionPoint) error {
+	orgID, err := tenantOrg(ctx)
+	if err != nil {
+		return err
+	}
+	point.OrgID = orgID
 	point.ID = primitive.NewObjectID()
-	_, err := m.db.Collection("points").InsertOne(ctx, point)
+	_, err = m.db.Collection("points").InsertOne(ctx, point)
 	return err
 }
 
 func (m *mongoRepository) UpdatePoint(ctx context.Context, id primitive.ObjectID, point *domain.CollectionPoint) error {
-	filter := bson.M{"_id": id}
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
 	update := bson.M{
 		"$set": bson.M{
 			"name":      point.Name,
@@ -120,147 +243,55 @@ func (m *mongoRepository) UpdatePoint(ctx context.Context, id primitive.ObjectID
 			"address":   point.Address,
 		},
 	}
-	_, err := m.db.Collection("points").UpdateOne(ctx, filter, update)
-	return err
+	res, err := m.db.Collection("points").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
 func (m *mongoRepository) DeletePoint(ctx context.Context, id primitive.ObjectID) error {
-	filter := bson.M{"_id": id}
-	_, err := m.db.Collection("points").DeleteOne(ctx, filter)
-	return err
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	res, err := m.db.Collection("points").DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
-// SeedWastes creates demo waste records for testing
-func (m *mongoRepository) SeedWastes(ctx context.Context) error {
-	collection := m.db.Collection("wastes")
-
-	// Eğer zaten veri varsa, tekrar ekleme
-	count, _ := collection.CountDocuments(ctx, bson.M{})
-	if count > 0 {
-		return nil
-	}
-
-	wasteRecords := []interface{}{
-		&domain.Waste{
-			ID:          primitive.NewObjectID(),
-			UserID:      "admin@example.com",
-			ImagePath:   "/uploads/waste_1.jpg",
-			Description: "Elektronik atık - Eski telefon",
-			Status:      "analyzed",
-			AIAnalysis: &domain.AIAnalysisResult{
-				FullyChargingPhones:              5,
-				LightHours:                       120,
-				LedLighting:                      450,
-				DrivingCar:                       25.5,
-				CO2Emission:                      125.5,
-				CleanWater:                       1200,
-				SoilDegradation:                  450,
-				ContaminatingGroundwater:         300,
-				EnergyConsumptionOfSmallWorkshop: 85,
-				LossRareEarthElements:            12.5,
-				MicroplasticPollutionMarineLife:  65,
-				AnnualCarbonSequestrationTree:    15,
-				HouseholdElectricityConsumption:  180,
-				DailyWaterConsumptionPeople:      200,
-				HumanCarbonFootprintOneDay:       2.5,
-				RiskDegree:                       8,
-				Cost:                             450000,
-			},
-			CreatedAt: time.Now().AddDate(0, 0, -5),
-		},
-		&domain.Waste{
-			ID:          primitive.NewObjectID(),
-			UserID:      "admin@example.com",
-			ImagePath:   "/uploads/waste_2.jpg",
-			Description: "Plastik atık - PET şişeler",
-			Status:      "pending",
-			AIAnalysis: &domain.AIAnalysisResult{
-				FullyChargingPhones:              2,
-				LightHours:                       45,
-				LedLighting:                      200,
-				DrivingCar:                       8.2,
-				CO2Emission:                      42.3,
-				CleanWater:                       450,
-				SoilDegradation:                  120,
-				ContaminatingGroundwater:         85,
-				EnergyConsumptionOfSmallWorkshop: 25,
-				LossRareEarthElements:            2.1,
-				MicroplasticPollutionMarineLife:  155,
-				AnnualCarbonSequestrationTree:    4,
-				HouseholdElectricityConsumption:  55,
-				DailyWaterConsumptionPeople:      75,
-				HumanCarbonFootprintOneDay:       0.8,
-				RiskDegree:                       4,
-				Cost:                             15000,
-			},
-			CreatedAt: time.Now().AddDate(0, 0, -3),
-		},
-		&domain.Waste{
-			ID:          primitive.NewObjectID(),
-			UserID:      "admin@example.com",
-			ImagePath:   "/uploads/waste_3.jpg",
-			Description: "Metal atık - Alüminyum kutular",
-			Status:      "collected",
-			AIAnalysis: &domain.AIAnalysisResult{
-				FullyChargingPhones:              8,
-				LightHours:                       200,
-				LedLighting:                      750,
-				DrivingCar:                       45.5,
-				CO2Emission:                      220.5,
-				CleanWater:                       2100,
-				SoilDegradation:                  800,
-				ContaminatingGroundwater:         520,
-				EnergyConsumptionOfSmallWorkshop: 145,
-				LossRareEarthElements:            28.5,
-				MicroplasticPollutionMarineLife:  12,
-				AnnualCarbonSequestrationTree:    32,
-				HouseholdElectricityConsumption:  320,
-				DailyWaterConsumptionPeople:      450,
-				HumanCarbonFootprintOneDay:       4.5,
-				RiskDegree:                       6,
-				Cost:                             750000,
-			},
-			CreatedAt: time.Now().AddDate(0, 0, -1),
-		},
-		&domain.Waste{
-			ID:          primitive.NewObjectID(),
-			UserID:      "admin@example.com",
-			ImagePath:   "/uploads/waste_4.jpg",
-			Description: "Cam atık - Şişeler ve kavanozlar",
-			Status:      "analyzed",
-			AIAnalysis: &domain.AIAnalysisResult{
-				FullyChargingPhones:              3,
-				LightHours:                       80,
-				LedLighting:                      300,
-				DrivingCar:                       12.5,
-				CO2Emission:                      60.5,
-				CleanWater:                       800,
-				SoilDegradation:                  200,
-				ContaminatingGroundwater:         150,
-				EnergyConsumptionOfSmallWorkshop: 40,
-				LossRareEarthElements:            5.5,
-				MicroplasticPollutionMarineLife:  8,
-				AnnualCarbonSequestrationTree:    8,
-				HouseholdElectricityConsumption:  90,
-				DailyWaterConsumptionPeople:      120,
-				HumanCarbonFootprintOneDay:       1.2,
-				RiskDegree:                       3,
-				Cost:                             120000,
-			},
-			CreatedAt: time.Now(),
-		},
+func (m *mongoRepository) GetPoint(ctx context.Context, id primitive.ObjectID) (*domain.CollectionPoint, error) {
+	filter, err := scoped(ctx, bson.M{"_id": id})
+	if err != nil {
+		return nil, err
 	}
-
-	_, err := collection.InsertMany(ctx, wasteRecords)
-	return err
+	var point domain.CollectionPoint
+	if err := m.db.Collection("points").FindOne(ctx, filter).Decode(&point); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &point, nil
 }
 
 // GetImpactStats - Gerçek Zamanlı Etki Analizi
 func (m *mongoRepository) GetImpactStats(ctx context.Context) (*domain.ImpactAnalysis, error) {
 	collection := m.db.Collection("wastes")
 
-	// Tüm analyzed ve collected durumundaki atıkları çek
-	filter := bson.M{"status": bson.M{"$in": []string{"analyzed", "pending", "collected"}}}
+	// Organizasyonun analyzed ve collected durumundaki atıklarını çek
+	filter, err := scoped(ctx, bson.M{"status": bson.M{"$in": []string{"analyzed", "pending", "collected"}}})
+	if err != nil {
+		return nil, err
+	}
 	cursor, err := collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -301,3 +332,57 @@ func (m *mongoRepository) GetImpactStats(ctx context.Context) (*domain.ImpactAna
 
 	return impact, nil
 }
+
+// --- KİŞİSEL VERİ (KVKK/GDPR) ---
+// Bu sorgular kullanıcıya göre yapılır; kullanıcı tek bir organizasyona ait olduğu için ayrıca kapsanmaz.
+
+func (m *mongoRepository) GetWastesByUser(ctx context.Context, userID string) ([]*domain.Waste, error) {
+	wastes := []*domain.Waste{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := m.db.Collection("wastes").Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &wastes); err != nil {
+		return nil, err
+	}
+	return wastes, nil
+}
+
+func (m *mongoRepository) GetRequestsByUser(ctx context.Context, userID string) ([]*domain.CollectionRequest, error) {
+	requests := []*domain.CollectionRequest{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := m.db.Collection("requests").Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (m *mongoRepository) AnonymizeUser(ctx context.Context, userID string) (int64, int64, error) {
+	// Etki analizi bozulmasın diye AI sonuçları ve durum korunur; kişiyi tanımlayan alanlar temizlenir
+	wastes, err := m.db.Collection("wastes").UpdateMany(
+		ctx,
+		bson.M{"user_id": userID},
+		bson.M{
+			"$set":   bson.M{"user_id": domain.ErasedUserID, "description": "", "image_path": ""},
+			"$unset": bson.M{"latitude": "", "longitude": ""},
+		},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	requests, err := m.db.Collection("requests").UpdateMany(
+		ctx,
+		bson.M{"user_id": userID},
+		bson.M{"$set": bson.M{"user_id": domain.ErasedUserID}},
+	)
+	if err != nil {
+		return wastes.ModifiedCount, 0, err
+	}
+	return wastes.ModifiedCount, requests.ModifiedCount, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"time"
+	"waste-service/internal/domain"
+)
+
+type auditLogger struct {
+	repo domain.AuditRepository
+}
+
+func NewAuditLogger(repo domain.AuditRepository) domain.AuditLogger {
+	return &auditLogger{repo: repo}
+}
+
+// Record aktör ve istemci bilgisini context'ten tamamlar
+func (l *auditLogger) Record(ctx context.Context, event domain.AuditEvent) {
+	actor := domain.AuditActorFromContext(ctx)
+	if event.ActorID == "" {
+		event.ActorID = actor.UserID
+	}
+	if event.ActorEmail == "" {
+		event.ActorEmail = actor.Email
+	}
+	if event.ImpersonatorID == "" {
+		event.ImpersonatorID = actor.ImpersonatorID
+		event.ImpersonatorEmail = actor.ImpersonatorEmail
+	}
+	if tenant, ok := domain.TenantFromContext(ctx); ok && event.OrgID == "" {
+		event.OrgID = tenant.OrgID
+	}
+	event.IP = actor.IP
+	event.UserAgent = actor.UserAgent
+	event.Service = domain.AuditServiceName
+	event.CreatedAt = time.Now()
+
+	if err := l.repo.Append(context.WithoutCancel(ctx), &event); err != nil {
+		log.Printf("Audit kaydı yazılamadı (%s %s/%s): %v", event.Action, event.TargetType, event.TargetID, err)
+	}
+}
+
+// auditDiff iki nesnenin JSON görünümünden sadece değişen alanları döner
+func auditDiff(before, after interface{}) (map[string]interface{}, map[string]interface{}) {
+	b, a := toAuditMap(before), toAuditMap(after)
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range b {
+		if !reflect.DeepEqual(value, a[key]) {
+			changedBefore[key] = value
+			if v, ok := a[key]; ok {
+				changedAfter[key] = v
+			}
+		}
+	}
+	for key, value := range a {
+		if _, ok := b[key]; !ok {
+			changedAfter[key] = value
+		}
+	}
+	return changedBefore, changedAfter
+}
+
+func toAuditMap(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	if v == nil {
+		return m
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
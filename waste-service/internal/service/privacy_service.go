@@ -0,0 +1,148 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+	"waste-service/internal/domain"
+)
+
+// Yüklenen resimlerin dizini; bu dizin dışındaki yollara dokunulmaz
+const uploadDir = "./uploads"
+
+type privacyService struct {
+	repo  domain.PrivacyRepository
+	audit domain.AuditLogger
+}
+
+func NewPrivacyService(repo domain.PrivacyRepository, audit domain.AuditLogger) domain.PrivacyService {
+	return &privacyService{repo: repo, audit: audit}
+}
+
+func (s *privacyService) Export(ctx context.Context, userID string) ([]byte, error) {
+	wastes, err := s.repo.GetWastesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.repo.GetRequestsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.PrivacyExport{
+		UserID:     userID,
+		ExportedAt: time.Now(),
+		Wastes:     wastes,
+		Requests:   requests,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	if err := writeJSON(archive, "wastes.json", export); err != nil {
+		return nil, err
+	}
+	for _, waste := range wastes {
+		local, ok := uploadPath(waste.ImagePath)
+		if !ok {
+			continue
+		}
+		if err := copyFile(archive, "images/"+waste.ID.Hex()+"_"+filepath.Base(local), local); err != nil {
+			if os.IsNotExist(err) {
+				continue // Kayıtta olup diskte olmayan resim
+			}
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *privacyService) Erase(ctx context.Context, userID string) (*domain.ErasureResult, error) {
+	wastes, err := s.repo.GetWastesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Önce resimler silinir: kayıt anonimleştikten sonra resmin sahibi bulunamaz
+	result := &domain.ErasureResult{}
+	for _, waste := range wastes {
+		local, ok := uploadPath(waste.ImagePath)
+		if !ok {
+			continue
+		}
+		if err := os.Remove(local); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("resim silinemedi (%s): %v", local, err)
+		}
+		result.ImagesDeleted++
+	}
+
+	result.WastesAnonymized, result.RequestsAnonymized, err = s.repo.AnonymizeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Kişisel veri silindi: %d atık, %d talep anonimleştirildi, %d resim silindi", result.WastesAnonymized, result.RequestsAnonymized, result.ImagesDeleted)
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditPrivacyErase,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID,
+		Metadata: map[string]interface{}{
+			"wastes_anonymized":   result.WastesAnonymized,
+			"requests_anonymized": result.RequestsAnonymized,
+			"images_deleted":      result.ImagesDeleted,
+		},
+	})
+	return result, nil
+}
+
+// uploadPath kayıttaki resim yolunu upload dizini içinde kalıyorsa döner
+func uploadPath(imagePath string) (string, bool) {
+	if imagePath == "" {
+		return "", false
+	}
+	clean := filepath.Clean(imagePath)
+	rel, err := filepath.Rel(filepath.Clean(uploadDir), clean)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return clean, true
+}
+
+func writeJSON(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func copyFile(archive *zip.Writer, name, local string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := archive.Create(path.Clean(name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
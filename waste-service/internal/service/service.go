@@ -20,11 +20,12 @@ import (
 type wasteService struct {
 	repo  domain.WasteRepository
 	aiURL string
+	audit domain.AuditLogger
 }
 
-func NewWasteService(repo domain.WasteRepository, aiURL string) domain.WasteService {
+func NewWasteService(repo domain.WasteRepository, aiURL string, audit domain.AuditLogger) domain.WasteService {
 	cleanURL := strings.TrimRight(aiURL, "/")
-	return &wasteService{repo: repo, aiURL: cleanURL}
+	return &wasteService{repo: repo, aiURL: cleanURL, audit: audit}
 }
 
 func (s *wasteService) UploadAndAnalyze(ctx context.Context, userID string, fileHeader interface{}, description string) (*domain.Waste, error) {
@@ -33,7 +34,6 @@ func (s *wasteService) UploadAndAnalyze(ctx context.Context, userID string, file
 	wd, _ := os.Getwd()
 	fmt.Println("Çalışma Dizini:", wd)
 
-	uploadDir := "./uploads"
 	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
 		return nil, fmt.Errorf("klasör oluşturulamadı: %v", err)
 	}
@@ -142,7 +142,23 @@ func (s *wasteService) UpdateWasteStatus(ctx context.Context, wasteID, status st
 	if err != nil {
 		return fmt.Errorf("geçersiz waste ID formatı")
 	}
-	return s.repo.UpdateWasteStatus(ctx, objID, status)
+	previous, err := s.repo.GetWaste(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateWasteStatus(ctx, objID, status); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, domain.AuditEvent{
+		Action:     domain.AuditWasteStatus,
+		OrgID:      previous.OrgID, // Süper admin başka bir organizasyonun kaydını değiştirmiş olabilir
+		TargetType: domain.AuditTargetWaste,
+		TargetID:   wasteID,
+		Before:     map[string]interface{}{"status": previous.Status},
+		After:      map[string]interface{}{"status": status},
+	})
+	return nil
 }
 
 func (s *wasteService) DeleteWaste(ctx context.Context, wasteID string) error {
@@ -150,7 +166,17 @@ func (s *wasteService) DeleteWaste(ctx context.Context, wasteID string) error {
 	if err != nil {
 		return fmt.Errorf("geçersiz waste ID formatı")
 	}
-	return s.repo.DeleteWaste(ctx, objID)
+	previous, err := s.repo.GetWaste(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteWaste(ctx, objID); err != nil {
+		return err
+	}
+
+	before, _ := auditDiff(previous, nil)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditWasteDelete, OrgID: previous.OrgID, TargetType: domain.AuditTargetWaste, TargetID: wasteID, Before: before})
+	return nil
 }
 
 // CreateWaste - Çoklu cihaz için waste oluştur (resim olmadan)
@@ -163,6 +189,14 @@ func (s *wasteService) CreateCollectionRequest(ctx context.Context, userID, wast
 	wID, _ := primitive.ObjectIDFromHex(wasteID)
 	pID, _ := primitive.ObjectIDFromHex(pointID)
 
+	// Atık ve nokta talebi oluşturanın organizasyonunda olmalı
+	if _, err := s.repo.GetWaste(ctx, wID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPoint(ctx, pID); err != nil {
+		return nil, err
+	}
+
 	req := &domain.CollectionRequest{
 		UserID:            userID,
 		WasteID:           wID,
@@ -176,7 +210,13 @@ func (s *wasteService) CreateCollectionRequest(ctx context.Context, userID, wast
 // --- YENİ EKLENEN METODLAR (NOKTA YÖNETİMİ) ---
 
 func (s *wasteService) CreatePoint(ctx context.Context, point *domain.CollectionPoint) error {
-	return s.repo.CreatePoint(ctx, point)
+	if err := s.repo.CreatePoint(ctx, point); err != nil {
+		return err
+	}
+
+	_, after := auditDiff(nil, point)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPointCreate, OrgID: point.OrgID, TargetType: domain.AuditTargetPoint, TargetID: point.ID.Hex(), After: after})
+	return nil
 }
 
 func (s *wasteService) UpdatePoint(ctx context.Context, pointID string, point *domain.CollectionPoint) error {
@@ -184,7 +224,21 @@ func (s *wasteService) UpdatePoint(ctx context.Context, pointID string, point *d
 	if err != nil {
 		return fmt.Errorf("geçersiz ID formatı")
 	}
-	return s.repo.UpdatePoint(ctx, objID, point)
+	previous, err := s.repo.GetPoint(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePoint(ctx, objID, point); err != nil {
+		return err
+	}
+
+	// Gövdede ID ve organizasyon olmayabilir; karşılaştırma sadece içerik üzerinden yapılır
+	updated := *point
+	updated.ID = objID
+	updated.OrgID = previous.OrgID
+	before, after := auditDiff(previous, &updated)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPointUpdate, OrgID: previous.OrgID, TargetType: domain.AuditTargetPoint, TargetID: pointID, Before: before, After: after})
+	return nil
 }
 
 func (s *wasteService) DeletePoint(ctx context.Context, pointID string) error {
@@ -192,7 +246,17 @@ func (s *wasteService) DeletePoint(ctx context.Context, pointID string) error {
 	if err != nil {
 		return fmt.Errorf("geçersiz ID formatı")
 	}
-	return s.repo.DeletePoint(ctx, objID)
+	previous, err := s.repo.GetPoint(ctx, objID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeletePoint(ctx, objID); err != nil {
+		return err
+	}
+
+	before, _ := auditDiff(previous, nil)
+	s.audit.Record(ctx, domain.AuditEvent{Action: domain.AuditPointDelete, OrgID: previous.OrgID, TargetType: domain.AuditTargetPoint, TargetID: pointID, Before: before})
+	return nil
 }
 
 // --- GERÇEK ZAMANLI ETKİ ANALİZİ ---